@@ -1,6 +1,7 @@
 package gobase
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,20 +9,21 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
-const (
-	mongoDBType = "mongodb"
-)
-
 // Accessor implements both ModelAccessor and MigrationProvider interfaces.
 // This follows the Interface Segregation Principle by implementing
 // focused interfaces, and the Single Responsibility Principle by
 // handling only data access operations.
 type Accessor struct {
-	connection *Connection
+	connection  *Connection
+	ctx         context.Context
+	deleted     DeletedScope
+	lockTimeout *time.Duration
+	lockTTL     *time.Duration
 }
 
 // NewAccessor creates a new Accessor instance with the provided database connection.
@@ -31,6 +33,81 @@ func NewAccessor(connection *Connection) *Accessor {
 	return &Accessor{connection: connection}
 }
 
+// WithContext returns a copy of the Accessor whose operations run with the
+// given context, so cancellation and deadlines reach the database driver.
+// The original Accessor is left unchanged.
+//
+//	err := accessor.WithContext(r.Context()).Filter(&articles, conditions)
+func (a *Accessor) WithContext(ctx context.Context) *Accessor {
+	scoped := *a
+	scoped.ctx = ctx
+	return &scoped
+}
+
+// Context returns the context used by the Accessor's operations.
+// It defaults to context.Background().
+func (a *Accessor) Context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// backend returns the Backend the Accessor's operations dispatch to.
+func (a *Accessor) backend() Backend {
+	return a.connection.backend()
+}
+
+// query returns a Query in the Accessor's soft-delete scope.
+func (a *Accessor) query() Query {
+	return Query{Deleted: a.deleted}
+}
+
+// queryCompiler returns the connection's backend as a QueryCompiler, if
+// it is one.
+func (a *Accessor) queryCompiler() (QueryCompiler, bool) {
+	compiler, ok := a.backend().(QueryCompiler)
+	return compiler, ok
+}
+
+// migrator returns the connection's backend as a Migrator, if it is one.
+func (a *Accessor) migrator() (Migrator, bool) {
+	migrator, ok := a.backend().(Migrator)
+	return migrator, ok
+}
+
+// requireQueryCompiler returns ErrNotSupported for operations built on
+// QuerySets when the connection's backend is not a QueryCompiler.
+func (a *Accessor) requireQueryCompiler(operation string) error {
+	if _, ok := a.queryCompiler(); !ok {
+		return fmt.Errorf("%w: %s on the %s backend", ErrNotSupported, operation, a.connection.Type)
+	}
+	return nil
+}
+
+// requireMigrator returns ErrNotSupported for schema operations when the
+// connection's backend is not a Migrator.
+func (a *Accessor) requireMigrator(operation string) error {
+	if _, ok := a.migrator(); !ok {
+		return fmt.Errorf("%w: %s on the %s backend", ErrNotSupported, operation, a.connection.Type)
+	}
+	return nil
+}
+
+// db returns the QueryCompiler's GORM session bound to the Accessor's
+// context. Callers check requireQueryCompiler first.
+func (a *Accessor) db() *gorm.DB {
+	compiler, _ := a.queryCompiler()
+	return compiler.QueryDB(a.Context())
+}
+
+// migrationDB returns the Migrator's GORM session bound to the Accessor's
+// context. Callers check requireMigrator first.
+func (a *Accessor) migrationDB() *gorm.DB {
+	migrator, _ := a.migrator()
+	return migrator.MigrationDB(a.Context())
+}
+
 // ValidateModel checks if the model properly embeds BaseModel
 func (a *Accessor) ValidateModel(model interface{}) error {
 	return ValidateBaseModel(model)
@@ -44,13 +121,7 @@ func (a *Accessor) Create(model interface{}) error {
 		return fmt.Errorf("model validation failed: %w", err)
 	}
 
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return errors.New("MongoDB support not yet implemented for Create operation")
-	}
-
-	result := a.connection.GormDB.Create(model)
-	return result.Error
+	return a.backend().Create(a.Context(), model)
 }
 
 // Get retrieves a record by its ID and populates the provided model.
@@ -64,79 +135,64 @@ func (a *Accessor) Get(model interface{}, id interface{}) error {
 		return errors.New("id cannot be nil")
 	}
 
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return errors.New("MongoDB support not yet implemented for Get operation")
-	}
-
-	// Handle both numeric and string IDs properly
-	result := a.connection.GormDB.Where("id = ?", id).First(model)
-	return result.Error
+	return a.backend().Get(a.Context(), model, id, a.query())
 }
 
 // All retrieves all records and populates the provided slice.
 // This method follows Django-style naming (All instead of FindAll).
 func (a *Accessor) All(models interface{}) error {
-	if models == nil {
-		return errors.New("models cannot be nil")
-	}
-
-	// Validate that models is a slice
-	rv := reflect.ValueOf(models)
-	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
-		return errors.New("models must be a pointer to a slice")
+	if _, err := a.sliceModel(models); err != nil {
+		return err
 	}
 
-	// Get the element type of the slice and validate it embeds BaseModel
-	sliceType := rv.Elem().Type()
-	elemType := sliceType.Elem()
-	if elemType.Kind() == reflect.Ptr {
-		elemType = elemType.Elem()
-	}
+	return a.backend().Find(a.Context(), models, a.query())
+}
 
-	// Create a temporary instance to validate
-	tempModel := reflect.New(elemType).Interface()
-	if err := a.ValidateModel(tempModel); err != nil {
-		return fmt.Errorf("model validation failed: %w", err)
+// Filter retrieves records based on conditions. Django-style filtering.
+// Condition keys support field lookups such as "views__gte" or
+// "title__icontains"; see QuerySet.Filter. On MongoDB the lookups are
+// translated to query operators, e.g. "views__gte" to $gte and
+// "title__icontains" to a case-insensitive $regex.
+func (a *Accessor) Filter(models interface{}, conditions map[string]interface{}) error {
+	if len(conditions) == 0 {
+		return a.All(models)
 	}
 
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return errors.New("MongoDB support not yet implemented for All operation")
+	if _, err := a.sliceModel(models); err != nil {
+		return err
 	}
 
-	result := a.connection.GormDB.Find(models)
-	return result.Error
+	query := a.query()
+	query.Conditions = conditions
+	return a.backend().Find(a.Context(), models, query)
 }
 
-// Filter retrieves records based on conditions. Django-style filtering.
-func (a *Accessor) Filter(models interface{}, conditions map[string]interface{}) error {
+// sliceModel validates that models is a pointer to a slice of a BaseModel
+// type and returns a new instance of the element type.
+func (a *Accessor) sliceModel(models interface{}) (interface{}, error) {
 	if models == nil {
-		return errors.New("models cannot be nil")
-	}
-
-	if len(conditions) == 0 {
-		return a.All(models)
+		return nil, errors.New("models cannot be nil")
 	}
 
 	// Validate that models is a slice
 	rv := reflect.ValueOf(models)
 	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
-		return errors.New("models must be a pointer to a slice")
+		return nil, errors.New("models must be a pointer to a slice")
 	}
 
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return errors.New("MongoDB support not yet implemented for Filter operation")
+	// Get the element type of the slice and validate it embeds BaseModel
+	elemType := rv.Elem().Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
 	}
 
-	query := a.connection.GormDB
-	for field, value := range conditions {
-		query = query.Where(fmt.Sprintf("%s = ?", field), value)
+	// Create a temporary instance to validate
+	tempModel := reflect.New(elemType).Interface()
+	if err := a.ValidateModel(tempModel); err != nil {
+		return nil, fmt.Errorf("model validation failed: %w", err)
 	}
 
-	result := query.Find(models)
-	return result.Error
+	return tempModel, nil
 }
 
 // Update saves changes to an existing record.
@@ -147,30 +203,19 @@ func (a *Accessor) Update(model interface{}) error {
 		return fmt.Errorf("model validation failed: %w", err)
 	}
 
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return errors.New("MongoDB support not yet implemented for Update operation")
-	}
-
-	result := a.connection.GormDB.Save(model)
-	return result.Error
+	return a.backend().Update(a.Context(), model)
 }
 
 // Delete performs a soft delete on the record.
 // This method follows the Single Responsibility Principle by only
-// handling record deletion.
+// handling record deletion. Related records are handled according to the
+// on_delete setting of the model's associations (see OnDeleteCascade).
 func (a *Accessor) Delete(model interface{}) error {
 	if err := a.ValidateModel(model); err != nil {
 		return fmt.Errorf("model validation failed: %w", err)
 	}
 
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return errors.New("MongoDB support not yet implemented for Delete operation")
-	}
-
-	result := a.connection.GormDB.Delete(model)
-	return result.Error
+	return a.backend().Delete(a.Context(), model)
 }
 
 // AutoMigrate automatically migrates the schema for all registered models.
@@ -178,12 +223,57 @@ func (a *Accessor) Delete(model interface{}) error {
 // Migrate performs database schema migration for the provided models.
 // If no models are provided, it will migrate all registered models.
 // The default User model is only migrated if explicitly used or passed as an argument.
+// Afterwards it applies the pending data migrations registered with
+// RegisterMigration, those without Up statements, in name order. Schema
+// migrations, such as those of generated migration files, are left to
+// MigrateTo: Migrate stops at the first pending one with
+// ErrSchemaMigrationPending rather than run later migrations before it.
+// It holds the migration lock while running (see WithMigrationLockTimeout).
+// On MongoDB it creates the models' collections and the indexes declared
+// in their gorm tags, with the validators of models implementing
+// MongoValidator; registered migrations are not run there.
 func (a *Accessor) Migrate(models ...interface{}) error {
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return errors.New("MongoDB support not yet implemented for Migrate operation")
+	modelsToMigrate := migrationModels(models)
+	if len(modelsToMigrate) == 0 {
+		return errors.New("no models to migrate")
+	}
+
+	return a.withMigrationLock(func() error {
+		if err := a.backend().Migrate(a.Context(), modelsToMigrate); err != nil {
+			return err
+		}
+
+		// Then run pending data migrations registered in Go
+		migrations := RegisteredMigrations()
+		if _, ok := a.migrator(); len(migrations) == 0 || !ok {
+			return nil
+		}
+		return a.applyDataMigrations(migrations)
+	})
+}
+
+// applyDataMigrations applies the pending migrations in order, up to the
+// first one with Up statements.
+func (a *Accessor) applyDataMigrations(migrations []Migration) error {
+	plan, err := a.MigrationPlan(migrations, "")
+	if err != nil {
+		return err
 	}
 
+	for _, step := range plan {
+		if len(step.Migration.Up) > 0 {
+			return fmt.Errorf("%w: apply %s with MigrateTo", ErrSchemaMigrationPending, step.Migration.Name)
+		}
+		if err := a.runMigrationStep(step, false); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", step.Migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// migrationModels returns the models to migrate: the given ones, or the
+// registered models when none are given.
+func migrationModels(models []interface{}) []interface{} {
 	var modelsToMigrate []interface{}
 
 	if len(models) > 0 {
@@ -204,11 +294,7 @@ func (a *Accessor) Migrate(models ...interface{}) error {
 		}
 	}
 
-	if len(modelsToMigrate) == 0 {
-		return errors.New("no models to migrate")
-	}
-
-	return a.connection.GormDB.AutoMigrate(modelsToMigrate...)
+	return modelsToMigrate
 }
 
 // isDefaultUserModel checks if the given model is the default gobase.User model
@@ -220,6 +306,10 @@ func isDefaultUserModel(model interface{}) bool {
 	return t.PkgPath() == "gobase" && t.Name() == "User"
 }
 
+// preloadBatchSize is the number of new records inserted per statement
+// when preloading.
+const preloadBatchSize = 100
+
 // Preload loads data from JSON files into the database
 func (a *Accessor) Preload(modelRegistry map[string]interface{}, jsonFilePaths ...string) error {
 	for _, filePath := range jsonFilePaths {
@@ -270,51 +360,70 @@ func (a *Accessor) preloadFromFile(modelRegistry map[string]interface{}, filePat
 		return fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
-	// Process each object
+	// Existing records are updated one by one; new records are inserted
+	// in batches
+	modelType := reflect.TypeOf(modelTemplate)
+	if modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	newRecords := reflect.New(reflect.SliceOf(reflect.PointerTo(modelType)))
+
 	for _, objData := range jsonObjects {
-		err = a.preloadObject(modelTemplate, objData)
+		newModel, exists, err := a.preloadObject(modelType, objData)
 		if err != nil {
 			return fmt.Errorf("failed to preload object: %w", err)
 		}
+
+		if exists {
+			if err := a.Update(newModel); err != nil {
+				return fmt.Errorf("failed to preload object: %w", err)
+			}
+			continue
+		}
+		newRecords.Elem().Set(reflect.Append(newRecords.Elem(), reflect.ValueOf(newModel)))
 	}
 
-	return nil
-}
+	// BulkCreate needs a QueryCompiler, so other backends insert one record
+	// at a time
+	if _, ok := a.queryCompiler(); !ok {
+		for i := 0; i < newRecords.Elem().Len(); i++ {
+			if err := a.Create(newRecords.Elem().Index(i).Interface()); err != nil {
+				return fmt.Errorf("failed to preload object: %w", err)
+			}
+		}
+		return nil
+	}
 
-// preloadObject processes a single JSON object and creates/updates the database record
-func (a *Accessor) preloadObject(modelTemplate interface{}, objData map[string]interface{}) error {
-	// Create a new instance of the model
-	modelType := reflect.TypeOf(modelTemplate)
-	if modelType.Kind() == reflect.Ptr {
-		modelType = modelType.Elem()
+	if _, err := a.BulkCreate(newRecords.Interface(), preloadBatchSize); err != nil {
+		return fmt.Errorf("failed to preload objects: %w", err)
 	}
 
+	return nil
+}
+
+// preloadObject decodes a single JSON object into a new instance of the
+// model and reports whether the corresponding record already exists
+func (a *Accessor) preloadObject(modelType reflect.Type, objData map[string]interface{}) (interface{}, bool, error) {
 	newModel := reflect.New(modelType).Interface()
 
 	// Convert JSON data to the model struct
 	jsonBytes, err := json.Marshal(objData)
 	if err != nil {
-		return fmt.Errorf("failed to marshal object data: %w", err)
+		return nil, false, fmt.Errorf("failed to marshal object data: %w", err)
 	}
 
 	err = json.Unmarshal(jsonBytes, newModel)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal to model: %w", err)
+		return nil, false, fmt.Errorf("failed to unmarshal to model: %w", err)
 	}
 
 	// Check if record already exists to prevent duplicates
 	exists, err := a.recordExists(newModel, objData)
 	if err != nil {
-		return fmt.Errorf("failed to check if record exists: %w", err)
+		return nil, false, fmt.Errorf("failed to check if record exists: %w", err)
 	}
 
-	if exists {
-		// Update existing record
-		return a.Update(newModel)
-	} else {
-		// Create new record
-		return a.Create(newModel)
-	}
+	return newModel, exists, nil
 }
 
 // recordExists checks if a record already exists based on unique fields
@@ -324,7 +433,7 @@ func (a *Accessor) recordExists(model interface{}, objData map[string]interface{
 		tempModel := reflect.New(reflect.TypeOf(model).Elem()).Interface()
 		err := a.Get(tempModel, id)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
+			if errors.Is(err, ErrDoesNotExist) {
 				return false, nil
 			}
 			return false, err
@@ -361,46 +470,53 @@ func Preload(accessor *Accessor, modelRegistry map[string]interface{}, jsonFileP
 // Advanced query methods that extend functionality while maintaining SOLID principles
 
 // FindWhere retrieves records based on a WHERE clause.
+// The condition is raw SQL and must never be built from user input; use
+// Filter for conditions coming from requests. On MongoDB the condition is
+// a query document in Extended JSON, e.g. `{"views": {"$gte": 10}}`,
+// without args.
 func (a *Accessor) FindWhere(models interface{}, condition string, args ...interface{}) error {
 	if models == nil {
 		return errors.New("models cannot be nil")
 	}
 
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return errors.New("MongoDB support not yet implemented for FindWhere operation")
-	}
-
-	result := a.connection.GormDB.Where(condition, args...).Find(models)
-	return result.Error
+	query := a.query()
+	query.Where, query.Args = condition, args
+	return a.backend().Find(a.Context(), models, query)
 }
 
 // Count returns the number of records matching the given conditions.
+// The condition is raw SQL and must never be built from user input; use
+// CountFilter for conditions coming from requests. On MongoDB it is a
+// query document, as for FindWhere.
 func (a *Accessor) Count(model interface{}, condition string, args ...interface{}) (int64, error) {
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return 0, errors.New("MongoDB support not yet implemented for Count operation")
+	query := a.query()
+	query.Where, query.Args = condition, args
+	return a.backend().Count(a.Context(), model, query)
+}
+
+// CountFilter returns the number of records matching the given Django-style
+// conditions. Like Filter, every condition key is validated against the
+// model's schema before it reaches the database.
+func (a *Accessor) CountFilter(model interface{}, conditions map[string]interface{}) (int64, error) {
+	if err := a.ValidateModel(model); err != nil {
+		return 0, fmt.Errorf("model validation failed: %w", err)
 	}
 
-	var count int64
-	result := a.connection.GormDB.Model(model).Where(condition, args...).Count(&count)
-	return count, result.Error
+	query := a.query()
+	query.Conditions = conditions
+	return a.backend().Count(a.Context(), model, query)
 }
 
 // Transaction executes a function within a database transaction.
 // This follows the Single Responsibility Principle by handling only transaction management.
+// On MongoDB, which requires a replica set or sharded cluster for
+// transactions, fn runs with an Accessor bound to a client session, and
+// is retried when the transaction fails with a TransientTransactionError.
 func (a *Accessor) Transaction(fn func(*Accessor) error) error {
-	// Only support GORM for now (SQLite/PostgreSQL)
-	if a.connection.Type == mongoDBType {
-		return errors.New("MongoDB support not yet implemented for Transaction operation")
-	}
-
-	return a.connection.GormDB.Transaction(func(tx *gorm.DB) error {
-		txConnection := &Connection{
-			Type:   a.connection.Type,
-			GormDB: tx,
-		}
-		txAccessor := NewAccessor(txConnection)
-		return fn(txAccessor)
+	err := a.backend().Transaction(a.Context(), func(tx Backend) error {
+		txAccessor := *a
+		txAccessor.connection = NewConnection(a.connection.Type, tx)
+		return fn(&txAccessor)
 	})
+	return translateError(err)
 }
@@ -1,7 +1,9 @@
 package gobase
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -614,3 +616,108 @@ func TestPreloadDuplicatePrevention(t *testing.T) {
 		t.Errorf("Expected 2 models (no duplicates), got %d", len(models))
 	}
 }
+
+// TestPreload_MemoryBackend tests preloading on a backend without BulkCreate
+func TestPreload_MemoryBackend(t *testing.T) {
+	accessor := NewAccessor(setupMemoryDB(t))
+	if err := accessor.Migrate(&TestModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	testData := []map[string]interface{}{
+		{"id": 1, "name": "Test Item 1"},
+		{"name": "Test Item 2"},
+	}
+	jsonData, err := json.Marshal(testData)
+	if err != nil {
+		t.Fatalf("Failed to marshal test data: %v", err)
+	}
+	tmpFile := filepath.Join(t.TempDir(), "test_models.json")
+	if err := os.WriteFile(tmpFile, jsonData, 0o644); err != nil {
+		t.Fatalf("Failed to write test data: %v", err)
+	}
+	modelRegistry := map[string]interface{}{
+		"test_models": &TestModel{},
+	}
+
+	if err := accessor.Preload(modelRegistry, tmpFile); err != nil {
+		t.Fatalf("First preload failed: %v", err)
+	}
+	// The record with an ID is updated; the one without is created again
+	if err := accessor.Preload(modelRegistry, tmpFile); err != nil {
+		t.Fatalf("Second preload failed: %v", err)
+	}
+
+	var models []TestModel
+	if err := accessor.All(&models); err != nil {
+		t.Fatalf("Failed to retrieve models: %v", err)
+	}
+	if len(models) != 3 {
+		t.Errorf("Expected 3 models, got %d", len(models))
+	}
+}
+
+// TestAccessor_WithContext tests that a cancelled context aborts a query
+func TestAccessor_WithContext(t *testing.T) {
+	accessor := setupArticles(t)
+
+	// An unbounded recursive CTE keeps SQLite busy until it is interrupted
+	slowCondition := "id = (WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT x FROM c WHERE x < 0 LIMIT 1)"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var articles []Article
+	err := accessor.WithContext(ctx).FindWhere(&articles, slowCondition)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected query to be aborted promptly, took %v", elapsed)
+	}
+
+	if ctx.Err() == nil {
+		t.Error("Expected context to be done")
+	}
+
+	// An already cancelled context must not reach the database at all
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+
+	err = accessor.WithContext(cancelled).Objects(&Article{}).All(&articles)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	ran := false
+	err = accessor.WithContext(cancelled).Transaction(func(tx *Accessor) error {
+		ran = true
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from Transaction, got %v", err)
+	}
+	if ran {
+		t.Error("Expected the transaction not to run with a cancelled context")
+	}
+
+	// The original accessor is unaffected by the scoped contexts
+	if err := accessor.All(&articles); err != nil {
+		t.Errorf("Expected unscoped accessor to keep working, got %v", err)
+	}
+
+	// Queries inside a transaction are aborted by the deadline too. This
+	// runs last: database/sql discards the connection of a transaction
+	// whose context ends, which drops the in-memory test database.
+	txCtx, txCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer txCancel()
+
+	err = accessor.WithContext(txCtx).Transaction(func(tx *Accessor) error {
+		return tx.FindWhere(&articles, slowCondition)
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded from Transaction, got %v", err)
+	}
+}
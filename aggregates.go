@@ -0,0 +1,303 @@
+package gobase
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Aggregate is an aggregate function over a model field, used with
+// QuerySet.Aggregate and QuerySet.Annotate. Build one with Sum, Avg, Min,
+// Max, Count or CountDistinct.
+type Aggregate struct {
+	function string
+	field    string
+	distinct bool
+}
+
+// Sum returns the sum of field. Sums of integer fields are returned as
+// int64, and the result is nil when no records match.
+func Sum(field string) Aggregate {
+	return Aggregate{function: "SUM", field: field}
+}
+
+// Avg returns the average of field as a float64, or nil when no records
+// match.
+func Avg(field string) Aggregate {
+	return Aggregate{function: "AVG", field: field}
+}
+
+// Min returns the smallest value of field, or nil when no records match.
+func Min(field string) Aggregate {
+	return Aggregate{function: "MIN", field: field}
+}
+
+// Max returns the largest value of field, or nil when no records match.
+func Max(field string) Aggregate {
+	return Aggregate{function: "MAX", field: field}
+}
+
+// Count returns the number of records with a non-NULL field. Pass "*" to
+// count records regardless of NULLs.
+func Count(field string) Aggregate {
+	return Aggregate{function: "COUNT", field: field}
+}
+
+// CountDistinct returns the number of distinct non-NULL values of field.
+func CountDistinct(field string) Aggregate {
+	return Aggregate{function: "COUNT", field: field, distinct: true}
+}
+
+// aggregateAlias restricts result names to plain identifiers, since they
+// are used as column aliases in the generated SQL.
+var aggregateAlias = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// GroupBy returns a new QuerySet whose Annotate results are grouped by the
+// given fields, like Django's values(...).annotate(...). Calling GroupBy
+// again replaces any previous grouping.
+func (qs *QuerySet) GroupBy(fields ...string) *QuerySet {
+	c := qs.clone()
+	c.groupBy = append([]string(nil), fields...)
+	return c
+}
+
+// Aggregate computes the given aggregates over the records matched by the
+// query and returns them keyed by name, like Django's aggregate():
+//
+//	stats, err := accessor.Objects(&Article{}).
+//		Filter(gobase.Q{"status": "published"}).
+//		Aggregate(map[string]gobase.Aggregate{
+//			"total_views": gobase.Sum("views"),
+//			"authors":     gobase.CountDistinct("author"),
+//		})
+//
+// Soft-deleted records are excluded. Limit and Offset, together with the
+// ordering, restrict the records that are aggregated.
+func (qs *QuerySet) Aggregate(aggregates map[string]Aggregate) (map[string]interface{}, error) {
+	sliced := qs.limit >= 0 || qs.offset > 0
+
+	// Ordering only matters for picking the records of a slice
+	base := qs.clone()
+	base.groupBy = nil
+	if !sliced {
+		base.orders = nil
+	}
+
+	db, err := base.build()
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := qs.accessor.modelSchema(qs.model)
+	if err != nil {
+		return nil, err
+	}
+
+	selectSQL, vars, err := aggregateSelect(s, nil, aggregates)
+	if err != nil {
+		return nil, err
+	}
+
+	if sliced {
+		// Aggregates ignore LIMIT/OFFSET, so aggregate the sliced rows in a
+		// subquery
+		db = qs.session().Table("(?) AS sliced", db)
+	}
+
+	results, err := scanRows(db.Select(selectSQL, vars...))
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	return results[0], nil
+}
+
+// Annotate computes the given aggregates for each group of records sharing
+// the GroupBy fields and returns one row per group. Each row holds the
+// group's column values and the named aggregates:
+//
+//	rows, err := accessor.Objects(&Article{}).
+//		GroupBy("author").
+//		OrderBy("-total_views").
+//		Annotate(map[string]gobase.Aggregate{"total_views": gobase.Sum("views")})
+//	// rows[0] == map[string]interface{}{"author": "bob", "total_views": int64(50)}
+//
+// Results may be ordered by grouped fields or annotation names and default
+// to the GroupBy order. Without GroupBy a single row covering every matched
+// record is returned. Limit and Offset apply to the groups.
+func (qs *QuerySet) Annotate(aggregates map[string]Aggregate) ([]map[string]interface{}, error) {
+	unordered := qs.clone()
+	unordered.orders = nil
+
+	db, err := unordered.build()
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := qs.accessor.modelSchema(qs.model)
+	if err != nil {
+		return nil, err
+	}
+
+	groupColumns := make([]clause.Column, 0, len(qs.groupBy))
+	grouped := make(map[string]bool, len(qs.groupBy))
+	for _, field := range qs.groupBy {
+		column, err := resolveField(s, field)
+		if err != nil {
+			return nil, err
+		}
+		groupColumns = append(groupColumns, clause.Column{Name: column})
+		grouped[column] = true
+	}
+
+	selectSQL, vars, err := aggregateSelect(s, groupColumns, aggregates)
+	if err != nil {
+		return nil, err
+	}
+	db = db.Select(selectSQL, vars...)
+
+	if len(groupColumns) > 0 {
+		db = db.Clauses(clause.GroupBy{Columns: groupColumns})
+	}
+
+	orders := qs.orders
+	if len(orders) == 0 {
+		orders = qs.groupBy
+	}
+	for _, field := range orders {
+		name := strings.TrimPrefix(field, "-")
+		column := name
+		if _, ok := aggregates[name]; !ok {
+			if column, err = resolveField(s, name); err != nil {
+				return nil, err
+			}
+			if !grouped[column] {
+				return nil, fmt.Errorf("cannot order by '%s': not a grouped field or annotation", name)
+			}
+		}
+		db = db.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: column},
+			Desc:   strings.HasPrefix(field, "-"),
+		})
+	}
+
+	return scanRows(db)
+}
+
+// scanRows executes the query and returns each row as a map keyed by
+// column name. Values keep the driver's natural types rather than the
+// model's field types, since aggregates rarely share a field's type.
+func scanRows(db *gorm.DB) ([]map[string]interface{}, error) {
+	rows, err := db.Rows()
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, translateError(err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[column] = values[i]
+		}
+		results = append(results, row)
+	}
+
+	return results, translateError(rows.Err())
+}
+
+// aggregateSelect builds the SELECT list for the group columns followed by
+// the named aggregates, in alias order.
+func aggregateSelect(s *schema.Schema, groupColumns []clause.Column, aggregates map[string]Aggregate) (string, []interface{}, error) {
+	if len(aggregates) == 0 {
+		return "", nil, errors.New("at least one aggregate is required")
+	}
+
+	parts := make([]string, 0, len(groupColumns)+len(aggregates))
+	vars := make([]interface{}, 0, len(groupColumns)+2*len(aggregates))
+	taken := make(map[string]bool, len(groupColumns))
+	for _, column := range groupColumns {
+		parts = append(parts, "?")
+		vars = append(vars, column)
+		taken[column.Name] = true
+	}
+
+	aliases := make([]string, 0, len(aggregates))
+	for alias := range aggregates {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		if !aggregateAlias.MatchString(alias) {
+			return "", nil, fmt.Errorf("invalid aggregate name '%s'", alias)
+		}
+		if taken[alias] {
+			return "", nil, fmt.Errorf("aggregate name '%s' conflicts with a grouped field", alias)
+		}
+
+		sql, aggregateVars, err := aggregates[alias].expr(s)
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, sql+" AS ?")
+		vars = append(vars, aggregateVars...)
+		vars = append(vars, clause.Column{Name: alias})
+	}
+
+	return strings.Join(parts, ", "), vars, nil
+}
+
+// expr builds the SQL for the aggregate against the model's schema.
+func (ag Aggregate) expr(s *schema.Schema) (string, []interface{}, error) {
+	if ag.function == "" {
+		return "", nil, errors.New("invalid aggregate: use Sum, Avg, Min, Max, Count or CountDistinct")
+	}
+	if ag.function == "COUNT" && !ag.distinct && (ag.field == "*" || ag.field == "") {
+		return "COUNT(*)", nil, nil
+	}
+
+	field := s.LookUpField(ag.field)
+	if field == nil || field.DBName == "" {
+		return "", nil, &FieldError{Field: ag.field, Model: s.Name}
+	}
+	column := clause.Column{Name: field.DBName}
+
+	switch {
+	case ag.distinct:
+		return "COUNT(DISTINCT ?)", []interface{}{column}, nil
+	case ag.function == "AVG":
+		// Keep averages fractional on every database
+		return "AVG(CAST(? AS DOUBLE PRECISION))", []interface{}{column}, nil
+	case ag.function == "SUM" && (field.DataType == schema.Int || field.DataType == schema.Uint):
+		// PostgreSQL widens integer sums to NUMERIC
+		return "CAST(SUM(?) AS BIGINT)", []interface{}{column}, nil
+	default:
+		return ag.function + "(?)", []interface{}{column}, nil
+	}
+}
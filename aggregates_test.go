@@ -0,0 +1,189 @@
+package gobase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestQuerySet_Aggregate tests aggregate functions over filtered querysets
+func TestQuerySet_Aggregate(t *testing.T) {
+	accessor := setupArticles(t)
+
+	tests := []struct {
+		name       string
+		queryset   *QuerySet
+		aggregates map[string]Aggregate
+		expected   map[string]interface{}
+	}{
+		{
+			name:     "All functions",
+			queryset: accessor.Objects(&Article{}),
+			aggregates: map[string]Aggregate{
+				"total":   Sum("views"),
+				"average": Avg("views"),
+				"lowest":  Min("views"),
+				"highest": Max("Views"),
+				"count":   Count("*"),
+				"authors": CountDistinct("author"),
+			},
+			expected: map[string]interface{}{
+				"total":   int64(90),
+				"average": 22.5,
+				"lowest":  int64(0),
+				"highest": int64(50),
+				"count":   int64(4),
+				"authors": int64(3),
+			},
+		},
+		{
+			name:       "Combined with Filter",
+			queryset:   accessor.Objects(&Article{}).Filter(Q{"status": "published"}).Exclude(Q{"author": "bob"}),
+			aggregates: map[string]Aggregate{"total": Sum("views"), "count": Count("id")},
+			expected:   map[string]interface{}{"total": int64(40), "count": int64(2)},
+		},
+		{
+			name:       "Sliced queryset",
+			queryset:   accessor.Objects(&Article{}).OrderBy("-views").Limit(2),
+			aggregates: map[string]Aggregate{"total": Sum("views")},
+			expected:   map[string]interface{}{"total": int64(80)},
+		},
+		{
+			name:       "No matches",
+			queryset:   accessor.Objects(&Article{}).Filter(Q{"author": "nobody"}),
+			aggregates: map[string]Aggregate{"total": Sum("views"), "count": Count("*")},
+			expected:   map[string]interface{}{"total": nil, "count": int64(0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.queryset.Aggregate(tt.aggregates)
+			if err != nil {
+				t.Fatalf("Aggregate failed: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestQuerySet_AggregateSoftDeleted tests that soft-deleted records are not aggregated
+func TestQuerySet_AggregateSoftDeleted(t *testing.T) {
+	accessor := setupArticles(t)
+
+	article := &Article{}
+	if err := accessor.Objects(&Article{}).Filter(Q{"title": "Advanced Go"}).Get(article); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := accessor.Delete(article); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	result, err := accessor.Objects(&Article{}).Aggregate(map[string]Aggregate{"total": Sum("views")})
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if result["total"] != int64(40) {
+		t.Errorf("Expected total 40, got %v", result["total"])
+	}
+
+	rows, err := accessor.Objects(&Article{}).GroupBy("author").Annotate(map[string]Aggregate{"count": Count("*")})
+	if err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Errorf("Expected 2 groups, got %v", rows)
+	}
+}
+
+// TestQuerySet_Annotate tests per-group aggregates
+func TestQuerySet_Annotate(t *testing.T) {
+	accessor := setupArticles(t)
+
+	tests := []struct {
+		name     string
+		queryset *QuerySet
+		expected []map[string]interface{}
+	}{
+		{
+			name:     "Group by author",
+			queryset: accessor.Objects(&Article{}).GroupBy("author"),
+			expected: []map[string]interface{}{
+				{"author": "alice", "count": int64(2), "total": int64(10)},
+				{"author": "bob", "count": int64(1), "total": int64(50)},
+				{"author": "carol", "count": int64(1), "total": int64(30)},
+			},
+		},
+		{
+			name:     "Ordered by annotation with Filter and Limit",
+			queryset: accessor.Objects(&Article{}).Filter(Q{"status": "published"}).GroupBy("Author").OrderBy("-total").Limit(2),
+			expected: []map[string]interface{}{
+				{"author": "bob", "count": int64(1), "total": int64(50)},
+				{"author": "carol", "count": int64(1), "total": int64(30)},
+			},
+		},
+		{
+			name:     "Multiple group fields",
+			queryset: accessor.Objects(&Article{}).Filter(Q{"author": "alice"}).GroupBy("author", "status").OrderBy("status"),
+			expected: []map[string]interface{}{
+				{"author": "alice", "status": "draft", "count": int64(1), "total": int64(0)},
+				{"author": "alice", "status": "published", "count": int64(1), "total": int64(10)},
+			},
+		},
+		{
+			name:     "Without GroupBy",
+			queryset: accessor.Objects(&Article{}),
+			expected: []map[string]interface{}{
+				{"count": int64(4), "total": int64(90)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tt.queryset.Annotate(map[string]Aggregate{"count": Count("*"), "total": Sum("views")})
+			if err != nil {
+				t.Fatalf("Annotate failed: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, rows)
+			}
+		})
+	}
+}
+
+// TestQuerySet_AggregateErrors tests validation of aggregate fields and names
+func TestQuerySet_AggregateErrors(t *testing.T) {
+	accessor := setupArticles(t)
+	qs := accessor.Objects(&Article{})
+
+	var fieldErr *FieldError
+	if _, err := qs.Aggregate(map[string]Aggregate{"total": Sum("missing")}); !errors.As(err, &fieldErr) {
+		t.Errorf("Expected *FieldError for unknown aggregate field, got %v", err)
+	}
+	if _, err := qs.GroupBy("missing").Annotate(map[string]Aggregate{"count": Count("*")}); !errors.As(err, &fieldErr) {
+		t.Errorf("Expected *FieldError for unknown group field, got %v", err)
+	}
+
+	invalid := []struct {
+		name       string
+		queryset   *QuerySet
+		aggregates map[string]Aggregate
+	}{
+		{name: "No aggregates", queryset: qs, aggregates: nil},
+		{name: "Invalid alias", queryset: qs, aggregates: map[string]Aggregate{"total; DROP TABLE articles": Sum("views")}},
+		{name: "Zero aggregate", queryset: qs, aggregates: map[string]Aggregate{"total": {}}},
+		{name: "Alias conflicts with group", queryset: qs.GroupBy("author"), aggregates: map[string]Aggregate{"author": Count("*")}},
+		{name: "Order by ungrouped field", queryset: qs.GroupBy("author").OrderBy("views"), aggregates: map[string]Aggregate{"count": Count("*")}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.queryset.Annotate(tt.aggregates); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
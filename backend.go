@@ -0,0 +1,156 @@
+package gobase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Backend is a database driver. An Accessor dispatches its operations to
+// the Backend of its Connection, so databases can be supported from outside
+// the package by registering a backend with RegisterBackend.
+//
+// Models passed to a Backend have been validated by the Accessor, and
+// errors should be reported with the package's sentinel errors, such as
+// ErrDoesNotExist and ErrUniqueViolation, so callers behave the same on
+// every database.
+type Backend interface {
+	// Create inserts model, assigning its primary key and timestamps.
+	Create(ctx context.Context, model interface{}) error
+	// Get loads the record of model whose primary key is id, among the
+	// records selected by query, or returns ErrDoesNotExist.
+	Get(ctx context.Context, model interface{}, id interface{}, query Query) error
+	// Find loads the records selected by query into models, a pointer to a
+	// slice of the model type.
+	Find(ctx context.Context, models interface{}, query Query) error
+	// Count returns the number of records of model selected by query.
+	Count(ctx context.Context, model interface{}, query Query) (int64, error)
+	// Update saves every field of model.
+	Update(ctx context.Context, model interface{}) error
+	// Delete soft-deletes model by setting its DeletedAt, or removes it
+	// when it has none.
+	Delete(ctx context.Context, model interface{}) error
+	// Migrate creates or updates the tables of models.
+	Migrate(ctx context.Context, models []interface{}) error
+	// Transaction runs fn with a Backend bound to a new transaction,
+	// committing it when fn returns nil and rolling it back otherwise.
+	Transaction(ctx context.Context, fn func(tx Backend) error) error
+	// Tables returns the names of the tables, or collections, in the
+	// database.
+	Tables(ctx context.Context) ([]string, error)
+	// Close closes the connection to the database.
+	Close() error
+}
+
+// QueryCompiler is an optional Backend capability, for backends whose
+// database GORM can query. The Accessor compiles QuerySets, and the
+// features built on them such as aggregates, pagination, bulk operations,
+// GetOrCreate, cascading deletes and soft-delete management, into SQL run
+// on the session it returns. On other backends these features return
+// ErrNotSupported.
+type QueryCompiler interface {
+	// QueryDB returns a GORM session on the backend's database bound to
+	// ctx.
+	QueryDB(ctx context.Context) *gorm.DB
+}
+
+// Migrator is an optional Backend capability, for backends whose schema
+// GORM can change and inspect. Versioned migrations, the migration lock,
+// InspectDB and CheckSchema run on the session it returns, and Migrate
+// only applies registered data migrations on backends implementing it.
+// On other backends these features return ErrNotSupported.
+type Migrator interface {
+	// MigrationDB returns a GORM session on the backend's database bound
+	// to ctx.
+	MigrationDB(ctx context.Context) *gorm.DB
+}
+
+// Query selects the records read by a Backend. Backends compile it to
+// their own query language: all of Conditions, which are Django-style
+// lookups like those of QuerySet.Filter, and Where, a raw condition in the
+// backend's language (such as SQL) with its Args, must match. Deleted is
+// the soft-delete scope.
+type Query struct {
+	Conditions map[string]interface{}
+	Where      string
+	Args       []interface{}
+	Deleted    DeletedScope
+}
+
+// BackendFactory opens a Backend for the database described by config.
+type BackendFactory func(config *DatabaseConfig) (Backend, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]BackendFactory{}
+)
+
+// RegisterBackend makes a database type available to InitDBWithConfig and
+// LoadConfig (as DB_TYPE) under name. It panics if name is already
+// registered or factory is nil, and is meant to be called from init:
+//
+//	func init() {
+//		gobase.RegisterBackend("mysql", func(config *gobase.DatabaseConfig) (gobase.Backend, error) {
+//			db, err := gorm.Open(mysql.Open(dsn(config)), &gorm.Config{})
+//			if err != nil {
+//				return nil, err
+//			}
+//			return gobase.NewGormBackend(db), nil
+//		})
+//	}
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if factory == nil {
+		panic("gobase: RegisterBackend factory is nil")
+	}
+	if _, exists := backends[name]; exists {
+		panic(fmt.Sprintf("gobase: RegisterBackend called twice for backend %q", name))
+	}
+	backends[name] = factory
+}
+
+// Backends returns the sorted names of the registered backends.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupBackend returns the factory registered under name.
+func lookupBackend(name string) (BackendFactory, bool) {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	factory, ok := backends[name]
+	return factory, ok
+}
+
+// autoTimestamp returns now in the representation of an auto-create or
+// auto-update time field, for backends that set timestamps themselves.
+func autoTimestamp(field *schema.Field, now time.Time, timeType schema.TimeType) interface{} {
+	switch timeType {
+	case schema.UnixNanosecond:
+		return now.UnixNano()
+	case schema.UnixMillisecond:
+		return now.UnixMilli()
+	case schema.UnixSecond:
+		if field.IndirectFieldType.Kind() != reflect.Struct {
+			return now.Unix()
+		}
+	}
+	return now
+}
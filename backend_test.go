@@ -0,0 +1,235 @@
+package gobase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// recordingBackend is a test Backend that records the queries it receives
+type recordingBackend struct {
+	queries []Query
+	tx      *recordingBackend
+	closed  bool
+}
+
+func (b *recordingBackend) Create(ctx context.Context, model interface{}) error {
+	return nil
+}
+
+func (b *recordingBackend) Get(ctx context.Context, model interface{}, id interface{}, query Query) error {
+	b.queries = append(b.queries, query)
+	return ErrDoesNotExist
+}
+
+func (b *recordingBackend) Find(ctx context.Context, models interface{}, query Query) error {
+	b.queries = append(b.queries, query)
+	return nil
+}
+
+func (b *recordingBackend) Count(ctx context.Context, model interface{}, query Query) (int64, error) {
+	b.queries = append(b.queries, query)
+	return 42, nil
+}
+
+func (b *recordingBackend) Update(ctx context.Context, model interface{}) error {
+	return nil
+}
+
+func (b *recordingBackend) Delete(ctx context.Context, model interface{}) error {
+	return nil
+}
+
+func (b *recordingBackend) Migrate(ctx context.Context, models []interface{}) error {
+	return nil
+}
+
+func (b *recordingBackend) Transaction(ctx context.Context, fn func(tx Backend) error) error {
+	b.tx = &recordingBackend{}
+	return fn(b.tx)
+}
+
+func (b *recordingBackend) Tables(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (b *recordingBackend) Close() error {
+	b.closed = true
+	return nil
+}
+
+// testBackend is registered once for the tests below
+var testBackend = &recordingBackend{}
+
+func init() {
+	RegisterBackend("recording", func(config *DatabaseConfig) (Backend, error) {
+		return testBackend, nil
+	})
+}
+
+// TestRegisterBackend tests the backend registry
+func TestRegisterBackend(t *testing.T) {
+	names := strings.Join(Backends(), ",")
+	if names != "memory,mongodb,postgres,recording,sqlite" {
+		t.Errorf("Expected the built-in and test backends, got %s", names)
+	}
+
+	tests := []struct {
+		name    string
+		backend string
+		factory BackendFactory
+	}{
+		{
+			name:    "Duplicate name",
+			backend: "sqlite",
+			factory: func(*DatabaseConfig) (Backend, error) { return nil, nil },
+		},
+		{
+			name:    "Nil factory",
+			backend: "nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected RegisterBackend to panic")
+				}
+			}()
+			RegisterBackend(tt.backend, tt.factory)
+		})
+	}
+}
+
+// TestLoadConfig_RegisteredBackend tests DB_TYPE accepting registered backends
+func TestLoadConfig_RegisteredBackend(t *testing.T) {
+	t.Setenv("DB_NAME", "app")
+
+	t.Setenv("DB_TYPE", "recording")
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if config.Type != "recording" {
+		t.Errorf("Expected type recording, got %s", config.Type)
+	}
+
+	t.Setenv("DB_TYPE", "oracle")
+	_, err = LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "memory, mongodb, postgres, recording, sqlite") {
+		t.Errorf("Expected an error listing the registered backends, got %v", err)
+	}
+}
+
+// TestAccessor_CustomBackend tests dispatching operations to a registered backend
+func TestAccessor_CustomBackend(t *testing.T) {
+	connection, err := InitDBWithConfig(&DatabaseConfig{Type: "recording", Name: "app"})
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+	if connection.Backend != testBackend || connection.GormDB != nil {
+		t.Fatalf("Expected a connection on the test backend, got %+v", connection)
+	}
+	accessor := NewAccessor(connection)
+	testBackend.queries = nil
+
+	var articles []Article
+	conditions := map[string]interface{}{"views__gt": 10}
+	if err := accessor.OnlyDeleted().Filter(&articles, conditions); err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if err := accessor.FindWhere(&articles, "views > ?", 10); err != nil {
+		t.Fatalf("FindWhere failed: %v", err)
+	}
+	if err := accessor.Get(&Article{}, 1); !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Expected ErrDoesNotExist, got %v", err)
+	}
+	count, err := accessor.Count(&Article{}, "views > ?", 10)
+	if err != nil || count != 42 {
+		t.Errorf("Expected the backend's count, got %d (%v)", count, err)
+	}
+
+	if len(testBackend.queries) != 4 {
+		t.Fatalf("Expected 4 queries, got %d", len(testBackend.queries))
+	}
+	if query := testBackend.queries[0]; query.Deleted != OnlyDeleted || query.Conditions["views__gt"] != 10 {
+		t.Errorf("Expected the Filter conditions in the deleted scope, got %+v", query)
+	}
+	if query := testBackend.queries[1]; query.Where != "views > ?" || len(query.Args) != 1 {
+		t.Errorf("Expected the FindWhere condition, got %+v", query)
+	}
+
+	err = accessor.Transaction(func(tx *Accessor) error {
+		if tx.connection.Backend != testBackend.tx {
+			t.Error("Expected the transaction's backend")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Transaction failed: %v", err)
+	}
+
+	// QuerySets compile to SQL, so need a GORM backend
+	if _, err := accessor.Objects(&Article{}).Count(); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+	if _, err := accessor.BulkCreate(&articles, 10); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+
+	if err := connection.Close(); err != nil || !testBackend.closed {
+		t.Errorf("Expected Close to close the backend, got %v", err)
+	}
+}
+
+// wrappedBackend wraps a GORM backend, like a driver adding
+// instrumentation, and forwards its optional capabilities
+type wrappedBackend struct {
+	Backend
+	sessions int
+}
+
+func (b *wrappedBackend) QueryDB(ctx context.Context) *gorm.DB {
+	b.sessions++
+	return b.Backend.(QueryCompiler).QueryDB(ctx)
+}
+
+func (b *wrappedBackend) MigrationDB(ctx context.Context) *gorm.DB {
+	b.sessions++
+	return b.Backend.(Migrator).MigrationDB(ctx)
+}
+
+// TestAccessor_BackendCapabilities tests dispatching GORM features to a custom backend's capabilities
+func TestAccessor_BackendCapabilities(t *testing.T) {
+	backend := &wrappedBackend{Backend: NewGormBackend(setupTestDB(t).GormDB)}
+	connection := NewConnection("wrapped", backend)
+	if connection.GormDB != nil {
+		t.Fatal("Expected a connection without a GORM handle")
+	}
+	accessor := NewAccessor(connection)
+
+	if err := accessor.Migrate(&Article{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	articles := []Article{{Title: "One", Views: 1}, {Title: "Two", Views: 2}}
+	if _, err := accessor.BulkCreate(&articles, 10); err != nil {
+		t.Fatalf("BulkCreate failed: %v", err)
+	}
+	count, err := accessor.Objects(&Article{}).Filter(Q{"views__gt": 1}).Count()
+	if err != nil || count != 1 {
+		t.Errorf("Expected 1 article from the QuerySet, got %d (%v)", count, err)
+	}
+	if _, err := accessor.MigrateTo(noteMigrations(), MigrateOptions{}); err != nil {
+		t.Errorf("MigrateTo failed: %v", err)
+	}
+	if _, err := accessor.CheckSchema(&Article{}); err != nil {
+		t.Errorf("CheckSchema failed: %v", err)
+	}
+	if backend.sessions == 0 {
+		t.Error("Expected the features to use the backend's sessions")
+	}
+}
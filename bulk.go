@@ -0,0 +1,275 @@
+package gobase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// bulkUpdateChunkSize caps the number of records updated per statement, so
+// that the generated CASE expressions stay within the databases' bound
+// parameter limits.
+const bulkUpdateChunkSize = 500
+
+// BulkCreate inserts the records of models, a slice or pointer to a slice of
+// a BaseModel type, using multi-row INSERT statements of up to batchSize
+// records each, and returns the number of records inserted. A batchSize of
+// zero or less inserts every record in a single statement.
+//
+// The model is validated once per call rather than once per record. Primary
+// keys are populated on the records, and when several batches are needed
+// they are inserted in a single transaction.
+func (a *Accessor) BulkCreate(models interface{}, batchSize int) (int64, error) {
+	models, _, length, err := a.bulkSlice(models)
+	if err != nil {
+		return 0, err
+	}
+
+	// Only supported by QueryCompiler backends
+	if err := a.requireQueryCompiler("BulkCreate"); err != nil {
+		return 0, err
+	}
+
+	if length == 0 {
+		return 0, nil
+	}
+	if batchSize <= 0 {
+		batchSize = length
+	}
+
+	result := a.db().CreateInBatches(models, batchSize)
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// BulkUpdate saves the given fields of the records of models, a slice or
+// pointer to a slice of a BaseModel type, and returns the number of records
+// updated. Like Django's bulk_update, each chunk of records is written with
+// a single UPDATE using CASE expressions keyed on the primary key, instead of
+// one statement per record.
+//
+// Every record must have a primary key. updated_at is refreshed on the
+// records and in the database. Model hooks are not run, and soft-deleted
+// records are left untouched.
+func (a *Accessor) BulkUpdate(models interface{}, fields ...string) (int64, error) {
+	models, model, length, err := a.bulkSlice(models)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(fields) == 0 {
+		return 0, errors.New("at least one field is required")
+	}
+
+	// Only supported by QueryCompiler backends
+	if err := a.requireQueryCompiler("BulkUpdate"); err != nil {
+		return 0, err
+	}
+
+	if length == 0 {
+		return 0, nil
+	}
+
+	s, err := a.modelSchema(model)
+	if err != nil {
+		return 0, err
+	}
+	if s.PrioritizedPrimaryField == nil {
+		return 0, fmt.Errorf("model '%s' has no primary key", s.Name)
+	}
+
+	updateFields := make([]*schema.Field, 0, len(fields))
+	for _, name := range fields {
+		field := s.LookUpField(name)
+		if field == nil || field.DBName == "" {
+			return 0, &FieldError{Field: name, Model: s.Name}
+		}
+		if field.PrimaryKey {
+			return 0, fmt.Errorf("cannot update primary key field '%s'", name)
+		}
+		updateFields = append(updateFields, field)
+	}
+
+	records := reflect.ValueOf(models).Elem()
+	now := a.db().NowFunc()
+
+	var affected int64
+	err = a.Transaction(func(tx *Accessor) error {
+		for start := 0; start < length; start += bulkUpdateChunkSize {
+			end := min(start+bulkUpdateChunkSize, length)
+			count, err := tx.bulkUpdateChunk(s, model, updateFields, records.Slice(start, end), now)
+			if err != nil {
+				return err
+			}
+			affected += count
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// bulkUpdateChunk writes one UPDATE statement for the given records.
+func (a *Accessor) bulkUpdateChunk(s *schema.Schema, model interface{}, fields []*schema.Field, records reflect.Value, now time.Time) (int64, error) {
+	ctx := a.Context()
+	db := a.db()
+	primary := s.PrioritizedPrimaryField
+	primaryColumn := clause.Column{Name: primary.DBName}
+
+	ids := make([]interface{}, records.Len())
+	for i := range ids {
+		id, zero := primary.ValueOf(ctx, records.Index(i))
+		if zero {
+			return 0, errors.New("all records must have a primary key")
+		}
+		ids[i] = id
+	}
+
+	assignments := make(map[string]interface{}, len(fields)+1)
+	for _, field := range fields {
+		// PostgreSQL cannot infer the type of bare CASE parameters
+		param := "?"
+		if db.Dialector.Name() == dialectPostgres {
+			param = fmt.Sprintf("CAST(? AS %s)", db.Dialector.DataTypeOf(field))
+		}
+
+		var sql strings.Builder
+		vars := make([]interface{}, 0, 2*len(ids)+2)
+		sql.WriteString("CASE ?")
+		vars = append(vars, primaryColumn)
+		for i, id := range ids {
+			value, _ := field.ValueOf(ctx, records.Index(i))
+			sql.WriteString(" WHEN ? THEN " + param)
+			vars = append(vars, id, value)
+		}
+		sql.WriteString(" ELSE ? END")
+		vars = append(vars, clause.Column{Name: field.DBName})
+
+		assignments[field.DBName] = clause.Expr{SQL: sql.String(), Vars: vars}
+	}
+
+	if updatedAt := touchUpdatedAt(s, assignments, now); updatedAt != nil {
+		for i := 0; i < records.Len(); i++ {
+			if err := updatedAt.Set(ctx, records.Index(i), now); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	result := db.Session(&gorm.Session{SkipHooks: true}).
+		Model(model).
+		Where(clause.IN{Column: primaryColumn, Values: ids}).
+		Updates(assignments)
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// UpdateWhere sets the given fields on every record of the model matching
+// the conditions in a single UPDATE statement and returns the number of
+// records updated. Conditions use the same lookups as Filter and must not be
+// empty; use Objects(model).Update to update every record.
+func (a *Accessor) UpdateWhere(model interface{}, conditions map[string]interface{}, values map[string]interface{}) (int64, error) {
+	if len(conditions) == 0 {
+		return 0, errors.New("conditions cannot be empty")
+	}
+	return a.Objects(model).Filter(Q(conditions)).Update(values)
+}
+
+// DeleteWhere soft-deletes every record of the model matching the
+// conditions in a single statement and returns the number of records
+// deleted. Conditions use the same lookups as Filter and must not be empty;
+// use Objects(model).Delete to delete every record.
+func (a *Accessor) DeleteWhere(model interface{}, conditions map[string]interface{}) (int64, error) {
+	if len(conditions) == 0 {
+		return 0, errors.New("conditions cannot be empty")
+	}
+	return a.Objects(model).Filter(Q(conditions)).Delete()
+}
+
+// Update sets the given fields on every record matched by the query in a
+// single UPDATE statement and returns the number of records updated, like
+// Django's QuerySet.update(). Keys are field names and are validated against
+// the model; updated_at is refreshed automatically. Soft-deleted records
+// are only updated when the QuerySet includes them. Model hooks are not run.
+func (qs *QuerySet) Update(values map[string]interface{}) (int64, error) {
+	if len(values) == 0 {
+		return 0, errors.New("values cannot be empty")
+	}
+	if qs.limit >= 0 || qs.offset > 0 {
+		return 0, errors.New("cannot update a sliced QuerySet")
+	}
+
+	db, err := qs.OrderBy().build()
+	if err != nil {
+		return 0, err
+	}
+
+	s, err := qs.accessor.modelSchema(qs.model)
+	if err != nil {
+		return 0, err
+	}
+
+	assignments := make(map[string]interface{}, len(values)+1)
+	for name, value := range values {
+		column, err := resolveField(s, name)
+		if err != nil {
+			return 0, err
+		}
+		assignments[column] = value
+	}
+	touchUpdatedAt(s, assignments, qs.accessor.db().NowFunc())
+
+	result := db.Session(&gorm.Session{SkipHooks: true, AllowGlobalUpdate: true}).Updates(assignments)
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// Delete soft-deletes every record matched by the query in a single
+// statement and returns the number of records deleted, like Django's
+// QuerySet.delete(). Records that are already soft-deleted are left
+// untouched, even with WithDeleted; use HardDelete to remove them. The
+// on-delete actions of the model's associations are applied. Model hooks
+// are not run.
+func (qs *QuerySet) Delete() (int64, error) {
+	return qs.deleteQuerySet(false)
+}
+
+// touchUpdatedAt adds the model's auto-update timestamp to assignments
+// unless it is set explicitly, and returns its field. GORM only does this
+// when hooks run, which bulk operations skip.
+func touchUpdatedAt(s *schema.Schema, assignments map[string]interface{}, now time.Time) *schema.Field {
+	for _, field := range s.Fields {
+		if field.AutoUpdateTime == 0 || field.DBName == "" || field.DataType != schema.Time {
+			continue
+		}
+		if _, ok := assignments[field.DBName]; ok {
+			return nil
+		}
+		assignments[field.DBName] = now
+		return field
+	}
+	return nil
+}
+
+// bulkSlice accepts a slice or a pointer to a slice of a BaseModel type and
+// returns a pointer to the slice, a new instance of the element type and
+// the number of records.
+func (a *Accessor) bulkSlice(models interface{}) (interface{}, interface{}, int, error) {
+	if rv := reflect.ValueOf(models); models != nil && rv.Kind() == reflect.Slice {
+		// Share the backing array so generated keys reach the caller
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		models = ptr.Interface()
+	}
+
+	model, err := a.sliceModel(models)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return models, model, reflect.ValueOf(models).Elem().Len(), nil
+}
@@ -0,0 +1,285 @@
+package gobase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// NotAModel is a struct that does not embed BaseModel
+type NotAModel struct {
+	Name string
+}
+
+// TestAccessor_BulkCreate tests batched inserts
+func TestAccessor_BulkCreate(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&Article{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		count     int
+		batchSize int
+	}{
+		{name: "Several batches", count: 25, batchSize: 10},
+		{name: "Single statement", count: 7, batchSize: 0},
+		{name: "Empty slice", count: 0, batchSize: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles := make([]Article, tt.count)
+			for i := range articles {
+				articles[i] = Article{Title: fmt.Sprintf("%s %d", tt.name, i), Status: "draft"}
+			}
+
+			affected, err := accessor.BulkCreate(articles, tt.batchSize)
+			if err != nil {
+				t.Fatalf("BulkCreate failed: %v", err)
+			}
+			if affected != int64(tt.count) {
+				t.Errorf("Expected %d rows affected, got %d", tt.count, affected)
+			}
+			for i, article := range articles {
+				if article.ID == 0 {
+					t.Errorf("Expected ID to be set on record %d", i)
+				}
+			}
+
+			count, err := accessor.Objects(&Article{}).Filter(Q{"title__startswith": tt.name}).Count()
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			if count != int64(tt.count) {
+				t.Errorf("Expected %d records, got %d", tt.count, count)
+			}
+		})
+	}
+}
+
+// TestAccessor_BulkCreateErrors tests validation and constraint errors
+func TestAccessor_BulkCreateErrors(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&User{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	if _, err := accessor.BulkCreate([]NotAModel{{Name: "x"}}, 10); err == nil {
+		t.Error("Expected validation error for model without BaseModel")
+	}
+	if _, err := accessor.BulkCreate(&User{}, 10); err == nil {
+		t.Error("Expected error for non-slice argument")
+	}
+
+	users := []*User{
+		{Username: "alice", Email: "alice@example.com", PasswordHash: "hash"},
+		{Username: "alice", Email: "other@example.com", PasswordHash: "hash"},
+	}
+	if _, err := accessor.BulkCreate(users, 1); !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("Expected ErrUniqueViolation, got %v", err)
+	}
+
+	// Batches run in one transaction, so the first user is rolled back
+	exists, err := accessor.Objects(&User{}).Filter(Q{"username": "alice"}).Exists()
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if exists {
+		t.Error("Expected failed BulkCreate to be rolled back")
+	}
+}
+
+// TestAccessor_BulkUpdate tests set-based updates of several records
+func TestAccessor_BulkUpdate(t *testing.T) {
+	accessor := setupArticles(t)
+
+	var articles []Article
+	if err := accessor.Objects(&Article{}).OrderBy("id").All(&articles); err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	before := articles[0].UpdatedAt
+	time.Sleep(10 * time.Millisecond)
+
+	for i := range articles {
+		articles[i].Views += 100
+		articles[i].Status = "archived"
+		articles[i].Author = "changed"
+	}
+
+	affected, err := accessor.BulkUpdate(&articles, "views", "Status")
+	if err != nil {
+		t.Fatalf("BulkUpdate failed: %v", err)
+	}
+	if affected != 4 {
+		t.Errorf("Expected 4 rows affected, got %d", affected)
+	}
+	if !articles[0].UpdatedAt.After(before) {
+		t.Error("Expected UpdatedAt to be refreshed on the records")
+	}
+
+	var reloaded []Article
+	if err := accessor.Objects(&Article{}).OrderBy("id").All(&reloaded); err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	expectedViews := []int{110, 150, 100, 130}
+	for i, article := range reloaded {
+		if article.Views != expectedViews[i] {
+			t.Errorf("Expected views %d for %s, got %d", expectedViews[i], article.Title, article.Views)
+		}
+		if article.Status != "archived" {
+			t.Errorf("Expected status archived for %s, got %s", article.Title, article.Status)
+		}
+		if article.Author == "changed" {
+			t.Errorf("Expected author of %s not to be updated", article.Title)
+		}
+		if !article.UpdatedAt.After(before) {
+			t.Errorf("Expected updated_at of %s to be refreshed", article.Title)
+		}
+	}
+}
+
+// TestAccessor_BulkUpdateErrors tests BulkUpdate argument validation
+func TestAccessor_BulkUpdateErrors(t *testing.T) {
+	accessor := setupArticles(t)
+
+	articles := []Article{{Title: "Unsaved"}}
+	tests := []struct {
+		name   string
+		models interface{}
+		fields []string
+	}{
+		{name: "No fields", models: articles},
+		{name: "Unknown field", models: articles, fields: []string{"missing"}},
+		{name: "Primary key", models: articles, fields: []string{"id"}},
+		{name: "Unsaved record", models: articles, fields: []string{"title"}},
+		{name: "Invalid model", models: []NotAModel{{Name: "x"}}, fields: []string{"name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := accessor.BulkUpdate(tt.models, tt.fields...); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+// TestQuerySet_UpdateDelete tests filter-scoped updates and deletes
+func TestQuerySet_UpdateDelete(t *testing.T) {
+	accessor := setupArticles(t)
+
+	affected, err := accessor.UpdateWhere(&Article{}, map[string]interface{}{"author": "alice"}, map[string]interface{}{"status": "archived", "Views": 1})
+	if err != nil {
+		t.Fatalf("UpdateWhere failed: %v", err)
+	}
+	if affected != 2 {
+		t.Errorf("Expected 2 rows updated, got %d", affected)
+	}
+
+	count, err := accessor.Objects(&Article{}).Filter(Q{"status": "archived", "views": 1}).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 archived articles, got %d", count)
+	}
+
+	affected, err = accessor.Objects(&Article{}).Filter(Q{"views__gte": 30}.Or(Q{"author": "alice"})).Exclude(Q{"author": "bob"}).Delete()
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if affected != 3 {
+		t.Errorf("Expected 3 rows deleted, got %d", affected)
+	}
+
+	// Deleted rows are soft-deleted and no longer updated
+	affected, err = accessor.Objects(&Article{}).Update(map[string]interface{}{"status": "final"})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if affected != 1 {
+		t.Errorf("Expected 1 row updated, got %d", affected)
+	}
+
+	affected, err = accessor.DeleteWhere(&Article{}, map[string]interface{}{"author": "bob"})
+	if err != nil {
+		t.Fatalf("DeleteWhere failed: %v", err)
+	}
+	if affected != 1 {
+		t.Errorf("Expected 1 row deleted, got %d", affected)
+	}
+
+	var unscoped int64
+	if err := accessor.connection.GormDB.Unscoped().Model(&Article{}).Count(&unscoped).Error; err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if unscoped != 4 {
+		t.Errorf("Expected soft-deleted rows to remain, got %d rows", unscoped)
+	}
+}
+
+// TestQuerySet_UpdateDeleteErrors tests validation of update and delete arguments
+func TestQuerySet_UpdateDeleteErrors(t *testing.T) {
+	accessor := setupArticles(t)
+	qs := accessor.Objects(&Article{})
+
+	var fieldErr *FieldError
+	if _, err := qs.Update(map[string]interface{}{"missing": 1}); !errors.As(err, &fieldErr) {
+		t.Errorf("Expected *FieldError, got %v", err)
+	}
+	if _, err := qs.Update(nil); err == nil {
+		t.Error("Expected error for empty values")
+	}
+	if _, err := qs.Limit(1).Update(map[string]interface{}{"views": 1}); err == nil {
+		t.Error("Expected error for sliced update")
+	}
+	if _, err := qs.Offset(1).Delete(); err == nil {
+		t.Error("Expected error for sliced delete")
+	}
+	if _, err := accessor.UpdateWhere(&Article{}, nil, map[string]interface{}{"views": 1}); err == nil {
+		t.Error("Expected error for UpdateWhere without conditions")
+	}
+	if _, err := accessor.DeleteWhere(&Article{}, map[string]interface{}{}); err == nil {
+		t.Error("Expected error for DeleteWhere without conditions")
+	}
+
+	count, err := qs.Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("Expected failed calls to leave 4 articles, got %d", count)
+	}
+}
+
+// TestManager_Bulk tests the typed bulk helpers
+func TestManager_Bulk(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&Article{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	articles := For[Article](accessor)
+
+	items := []Article{{Title: "One"}, {Title: "Two"}}
+	if _, err := articles.BulkCreate(items, 10); err != nil {
+		t.Fatalf("BulkCreate failed: %v", err)
+	}
+
+	items[0].Views, items[1].Views = 5, 7
+	if _, err := articles.BulkUpdate(items, "views"); err != nil {
+		t.Fatalf("BulkUpdate failed: %v", err)
+	}
+
+	result, err := articles.Objects().Aggregate(map[string]Aggregate{"total": Sum("views")})
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if result["total"] != int64(12) {
+		t.Errorf("Expected total views 12, got %v", result["total"])
+	}
+}
@@ -0,0 +1,354 @@
+package gobase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// On-delete actions for has-one and has-many associations, mirroring
+// Django's on_delete. They are declared on the parent's association field
+// with a gobase struct tag and are honoured by soft and hard deletes:
+//
+//	type Author struct {
+//		gobase.BaseModel
+//		Name  string
+//		Books []Book `gobase:"on_delete:CASCADE"`
+//	}
+//
+// Associations without an on_delete setting behave as DO_NOTHING.
+const (
+	// OnDeleteCascade deletes the related records along with the parent.
+	OnDeleteCascade = "CASCADE"
+	// OnDeleteSetNull clears the related records' foreign key. The foreign
+	// key field must be nullable, e.g. *uint.
+	OnDeleteSetNull = "SET_NULL"
+	// OnDeleteProtect refuses to delete a parent that still has related
+	// records, returning a *ProtectedError.
+	OnDeleteProtect = "PROTECT"
+	// OnDeleteDoNothing leaves the related records untouched.
+	OnDeleteDoNothing = "DO_NOTHING"
+)
+
+// ProtectedError is returned when a delete is blocked by an association
+// declared with OnDeleteProtect. errors.Is matches it against ErrProtected.
+type ProtectedError struct {
+	Model        string        // model being deleted
+	Field        string        // protecting association field on Model
+	RelatedModel string        // model of the blocking records
+	Objects      []interface{} // blocking records, as pointers to RelatedModel
+}
+
+// Error implements the error interface.
+func (e *ProtectedError) Error() string {
+	return fmt.Sprintf("cannot delete %s: %d related %s record(s) are protected by %s.%s",
+		e.Model, len(e.Objects), e.RelatedModel, e.Model, e.Field)
+}
+
+// Is reports whether target is ErrProtected.
+func (e *ProtectedError) Is(target error) bool {
+	return target == ErrProtected
+}
+
+// onDeleteRelation is an association with its on-delete action.
+type onDeleteRelation struct {
+	relation *schema.Relationship
+	action   string
+}
+
+// scannerType is used to recognise nullable foreign key types such as
+// sql.NullInt64.
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// onDeleteRelations returns the associations of s that declare an on-delete
+// action other than DO_NOTHING, in field name order.
+func onDeleteRelations(s *schema.Schema) ([]onDeleteRelation, error) {
+	names := make([]string, 0, len(s.Relationships.Relations))
+	for name := range s.Relationships.Relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var relations []onDeleteRelation
+	for _, name := range names {
+		relation := s.Relationships.Relations[name]
+		if relation.Schema.ModelType != s.ModelType {
+			// GORM also registers back-references here for some
+			// associations declared on other models
+			continue
+		}
+		settings := schema.ParseTagSetting(relation.Field.Tag.Get("gobase"), ";")
+		action := strings.ToUpper(strings.TrimSpace(settings["ON_DELETE"]))
+		if action == "" || action == OnDeleteDoNothing {
+			continue
+		}
+
+		switch action {
+		case OnDeleteCascade, OnDeleteSetNull, OnDeleteProtect:
+		default:
+			return nil, fmt.Errorf("invalid on_delete %q on %s.%s", action, s.Name, name)
+		}
+
+		if relation.Type != schema.HasOne && relation.Type != schema.HasMany {
+			return nil, fmt.Errorf("on_delete on %s.%s requires a has-one or has-many association", s.Name, name)
+		}
+		for _, ref := range relation.References {
+			if ref.OwnPrimaryKey && !ref.PrimaryKey.PrimaryKey {
+				return nil, fmt.Errorf("on_delete on %s.%s requires a foreign key to the primary key", s.Name, name)
+			}
+			if action == OnDeleteSetNull && ref.OwnPrimaryKey && !nullableField(ref.ForeignKey) {
+				return nil, fmt.Errorf("on_delete SET_NULL on %s.%s requires a nullable foreign key %s.%s",
+					s.Name, name, relation.FieldSchema.Name, ref.ForeignKey.Name)
+			}
+		}
+
+		relations = append(relations, onDeleteRelation{relation: relation, action: action})
+	}
+	return relations, nil
+}
+
+// nullableField reports whether the field can hold NULL.
+func nullableField(field *schema.Field) bool {
+	return field.FieldType.Kind() == reflect.Ptr || reflect.PointerTo(field.FieldType).Implements(scannerType)
+}
+
+// cascade applies the on-delete actions of s's associations for the
+// records with the given primary keys, which are about to be deleted. It
+// must run inside a transaction together with the delete.
+func (a *Accessor) cascade(s *schema.Schema, ids []interface{}, hard bool) error {
+	visited := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		visited[recordKey(s, id)] = true
+	}
+	return a.deleteRelated(s, ids, hard, a.db().NowFunc(), visited)
+}
+
+// deleteRelated applies the on-delete actions of s's associations to the
+// records related to the parents with the given primary keys. Soft deletes
+// only consider live related records; hard deletes consider soft-deleted
+// ones too, as they would otherwise be orphaned.
+func (a *Accessor) deleteRelated(s *schema.Schema, ids []interface{}, hard bool, now time.Time, visited map[string]bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	relations, err := onDeleteRelations(s)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range relations {
+		related := r.relation.FieldSchema
+		model := reflect.New(related.ModelType).Interface()
+
+		query := func() *gorm.DB {
+			db := a.db().Model(model)
+			if hard {
+				db = db.Unscoped()
+			}
+			for _, ref := range r.relation.References {
+				if ref.OwnPrimaryKey {
+					db = db.Where(clause.IN{Column: clause.Column{Name: ref.ForeignKey.DBName}, Values: ids})
+				} else if ref.PrimaryValue != "" {
+					db = db.Where(clause.Eq{Column: clause.Column{Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+				}
+			}
+			return db
+		}
+
+		switch r.action {
+		case OnDeleteProtect:
+			objects := reflect.New(reflect.SliceOf(reflect.PointerTo(related.ModelType)))
+			if err := query().Find(objects.Interface()).Error; err != nil {
+				return translateError(err)
+			}
+			if objects.Elem().Len() > 0 {
+				protectedErr := &ProtectedError{Model: s.Name, Field: r.relation.Name, RelatedModel: related.Name}
+				for i := 0; i < objects.Elem().Len(); i++ {
+					protectedErr.Objects = append(protectedErr.Objects, objects.Elem().Index(i).Interface())
+				}
+				return protectedErr
+			}
+
+		case OnDeleteSetNull:
+			assignments := map[string]interface{}{}
+			for _, ref := range r.relation.References {
+				if ref.OwnPrimaryKey {
+					assignments[ref.ForeignKey.DBName] = nil
+				}
+			}
+			touchUpdatedAt(related, assignments, now)
+			if err := query().Session(&gorm.Session{SkipHooks: true}).Updates(assignments).Error; err != nil {
+				return translateError(err)
+			}
+
+		case OnDeleteCascade:
+			primary := related.PrioritizedPrimaryField
+			if primary == nil {
+				return fmt.Errorf("model '%s' has no primary key", related.Name)
+			}
+
+			var relatedIDs []interface{}
+			if err := query().Pluck(primary.DBName, &relatedIDs).Error; err != nil {
+				return translateError(err)
+			}
+
+			// Skip records already being deleted, so cycles terminate
+			pending := relatedIDs[:0]
+			for _, id := range relatedIDs {
+				if key := recordKey(related, id); !visited[key] {
+					visited[key] = true
+					pending = append(pending, id)
+				}
+			}
+			if len(pending) == 0 {
+				continue
+			}
+
+			if err := a.deleteRelated(related, pending, hard, now, visited); err != nil {
+				return err
+			}
+
+			db := a.db().Session(&gorm.Session{SkipHooks: true})
+			if hard {
+				db = db.Unscoped()
+			}
+			result := db.Where(clause.IN{Column: clause.Column{Name: primary.DBName}, Values: pending}).Delete(model)
+			if result.Error != nil {
+				return translateError(result.Error)
+			}
+		}
+	}
+
+	return nil
+}
+
+// recordKey identifies a record across models for cycle detection.
+func recordKey(s *schema.Schema, id interface{}) string {
+	return s.Table + ":" + fmt.Sprint(id)
+}
+
+// deleteModel deletes a single record, applying the on-delete actions of
+// its associations when it declares any.
+func (a *Accessor) deleteModel(model interface{}, hard bool) error {
+	s, err := a.modelSchema(model)
+	if err != nil {
+		return err
+	}
+	relations, err := onDeleteRelations(s)
+	if err != nil {
+		return err
+	}
+
+	execute := func(tx *Accessor) error {
+		db := tx.db()
+		if hard {
+			db = db.Unscoped()
+		}
+		return translateError(db.Delete(model).Error)
+	}
+
+	var id interface{}
+	zero := true
+	if s.PrioritizedPrimaryField != nil {
+		id, zero = s.PrioritizedPrimaryField.ValueOf(a.Context(), reflect.ValueOf(model))
+	}
+	if len(relations) == 0 || zero {
+		return execute(a)
+	}
+
+	return a.Transaction(func(tx *Accessor) error {
+		if err := tx.cascade(s, []interface{}{id}, hard); err != nil {
+			return err
+		}
+		return execute(tx)
+	})
+}
+
+// deleteQuerySet deletes the records matched by qs in a single statement
+// and returns the number deleted, first applying the on-delete actions of
+// their associations when the model declares any. Soft deletes never touch
+// records that are already soft-deleted.
+func (qs *QuerySet) deleteQuerySet(hard bool) (int64, error) {
+	if qs.limit >= 0 || qs.offset > 0 {
+		return 0, errors.New("cannot delete a sliced QuerySet")
+	}
+
+	scoped := qs.OrderBy()
+	if !hard {
+		scoped.deleted = ExcludeDeleted
+	}
+
+	db, err := scoped.build()
+	if err != nil {
+		return 0, err
+	}
+
+	s, err := qs.accessor.modelSchema(qs.model)
+	if err != nil {
+		return 0, err
+	}
+	relations, err := onDeleteRelations(s)
+	if err != nil {
+		return 0, err
+	}
+
+	target := reflect.New(reflect.Indirect(reflect.ValueOf(qs.model)).Type()).Interface()
+	execute := func(db *gorm.DB) (int64, error) {
+		db = db.Session(&gorm.Session{SkipHooks: true, AllowGlobalUpdate: true})
+		if hard {
+			if scoped.deleted == ExcludeDeleted {
+				// Unscoped also drops the implicit "deleted_at IS NULL"
+				db = db.Where(deletedAtCondition(s, false))
+			}
+			db = db.Unscoped()
+		}
+		result := db.Delete(target)
+		return result.RowsAffected, translateError(result.Error)
+	}
+
+	if len(relations) == 0 {
+		return execute(db)
+	}
+
+	primary := s.PrioritizedPrimaryField
+	if primary == nil {
+		return 0, fmt.Errorf("model '%s' has no primary key", s.Name)
+	}
+
+	var affected int64
+	err = qs.accessor.Transaction(func(tx *Accessor) error {
+		scoped.accessor = tx
+		db, err := scoped.build()
+		if err != nil {
+			return err
+		}
+
+		var ids []interface{}
+		if err := db.Pluck(primary.DBName, &ids).Error; err != nil {
+			return translateError(err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.cascade(s, ids, hard); err != nil {
+			return err
+		}
+
+		affected, err = execute(tx.db().Model(target).Where(clause.IN{Column: clause.Column{Name: primary.DBName}, Values: ids}))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
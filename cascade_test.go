@@ -0,0 +1,313 @@
+package gobase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Publisher is a test model exercising every on_delete action
+type Publisher struct {
+	BaseModel
+	Name     string
+	Books    []Book    `gobase:"on_delete:CASCADE"`
+	Editors  []Editor  `gobase:"on_delete:SET_NULL"`
+	Contract *Contract `gobase:"on_delete:PROTECT"`
+	Reviews  []Review
+}
+
+// Book cascades to its chapters
+type Book struct {
+	BaseModel
+	Title       string
+	PublisherID uint
+	Chapters    []Chapter `gobase:"on_delete:CASCADE"`
+}
+
+// Chapter belongs to a book
+type Chapter struct {
+	BaseModel
+	Title  string
+	BookID uint
+}
+
+// Editor has a nullable foreign key to its publisher
+type Editor struct {
+	BaseModel
+	Name        string
+	PublisherID *uint
+}
+
+// Contract protects its publisher from deletion
+type Contract struct {
+	BaseModel
+	Terms       string
+	PublisherID uint
+}
+
+// Review is left untouched when its publisher is deleted
+type Review struct {
+	BaseModel
+	Body        string
+	PublisherID uint
+}
+
+// InvalidOnDelete declares an unknown on_delete action
+type InvalidOnDelete struct {
+	BaseModel
+	Chapters []Chapter `gorm:"foreignKey:BookID" gobase:"on_delete:EXPLODE"`
+}
+
+// NonNullSetNull declares SET_NULL on a non-nullable foreign key
+type NonNullSetNull struct {
+	BaseModel
+	Reviews []Review `gorm:"foreignKey:PublisherID" gobase:"on_delete:SET_NULL"`
+}
+
+// setupPublishers creates two publishers with books, chapters, editors and reviews
+func setupPublishers(t *testing.T) (*Accessor, []*Publisher) {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&Publisher{}, &Book{}, &Chapter{}, &Editor{}, &Contract{}, &Review{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	var publishers []*Publisher
+	for _, name := range []string{"Acme", "Globex"} {
+		publisher := &Publisher{
+			Name: name,
+			Books: []Book{
+				{Title: name + " One", Chapters: []Chapter{{Title: "Intro"}, {Title: "Outro"}}},
+				{Title: name + " Two", Chapters: []Chapter{{Title: "Intro"}}},
+			},
+			Editors: []Editor{{Name: name + " Editor"}},
+			Reviews: []Review{{Body: name + " review"}},
+		}
+		if err := accessor.Create(publisher); err != nil {
+			t.Fatalf("Failed to create publisher: %v", err)
+		}
+		publishers = append(publishers, publisher)
+	}
+
+	return accessor, publishers
+}
+
+// countModels counts the live and soft-deleted records of a model
+func countModels(t *testing.T, accessor *Accessor, model interface{}) (int64, int64) {
+	live, err := accessor.Objects(model).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	all, err := accessor.Objects(model).WithDeleted().Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	return live, all
+}
+
+// TestAccessor_DeleteCascade tests on_delete actions on soft deletes
+func TestAccessor_DeleteCascade(t *testing.T) {
+	accessor, publishers := setupPublishers(t)
+
+	if err := accessor.Delete(publishers[0]); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		model        interface{}
+		expectedLive int64
+		expectedAll  int64
+	}{
+		{name: "Publisher", model: &Publisher{}, expectedLive: 1, expectedAll: 2},
+		{name: "CASCADE", model: &Book{}, expectedLive: 2, expectedAll: 4},
+		{name: "Nested CASCADE", model: &Chapter{}, expectedLive: 3, expectedAll: 6},
+		{name: "SET_NULL", model: &Editor{}, expectedLive: 2, expectedAll: 2},
+		{name: "DO_NOTHING", model: &Review{}, expectedLive: 2, expectedAll: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live, all := countModels(t, accessor, tt.model)
+			if live != tt.expectedLive || all != tt.expectedAll {
+				t.Errorf("Expected %d live of %d, got %d live of %d", tt.expectedLive, tt.expectedAll, live, all)
+			}
+		})
+	}
+
+	editor := &Editor{}
+	if err := accessor.Objects(&Editor{}).Filter(Q{"name": "Acme Editor"}).Get(editor); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if editor.PublisherID != nil {
+		t.Errorf("Expected editor's publisher to be cleared, got %d", *editor.PublisherID)
+	}
+
+	// The other publisher's records are untouched
+	count, err := accessor.Objects(&Book{}).Filter(Q{"publisher_id": publishers[1].ID}).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 books for Globex, got %d", count)
+	}
+}
+
+// TestAccessor_HardDeleteCascade tests on_delete actions on hard deletes
+func TestAccessor_HardDeleteCascade(t *testing.T) {
+	accessor, publishers := setupPublishers(t)
+
+	// Soft-deleted related records are removed too
+	book := &Book{}
+	if err := accessor.Objects(&Book{}).Filter(Q{"title": "Acme Two"}).Get(book); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := accessor.Delete(book); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if err := accessor.HardDelete(publishers[0]); err != nil {
+		t.Fatalf("HardDelete failed: %v", err)
+	}
+
+	if _, all := countModels(t, accessor, &Book{}); all != 2 {
+		t.Errorf("Expected 2 books to remain, got %d", all)
+	}
+	if _, all := countModels(t, accessor, &Chapter{}); all != 3 {
+		t.Errorf("Expected 3 chapters to remain, got %d", all)
+	}
+	if _, all := countModels(t, accessor, &Review{}); all != 2 {
+		t.Errorf("Expected reviews to be untouched, got %d", all)
+	}
+}
+
+// TestAccessor_DeleteProtect tests that PROTECT blocks deletes
+func TestAccessor_DeleteProtect(t *testing.T) {
+	accessor, publishers := setupPublishers(t)
+
+	contract := &Contract{Terms: "Exclusive", PublisherID: publishers[0].ID}
+	if err := accessor.Create(contract); err != nil {
+		t.Fatalf("Failed to create contract: %v", err)
+	}
+
+	deletes := map[string]func() error{
+		"Delete":     func() error { return accessor.Delete(publishers[0]) },
+		"HardDelete": func() error { return accessor.HardDelete(publishers[0]) },
+		"QuerySet.Delete": func() error {
+			_, err := accessor.Objects(&Publisher{}).Delete()
+			return err
+		},
+	}
+
+	for name, del := range deletes {
+		t.Run(name, func(t *testing.T) {
+			err := del()
+			if !errors.Is(err, ErrProtected) {
+				t.Fatalf("Expected ErrProtected, got %v", err)
+			}
+
+			var protectedErr *ProtectedError
+			if !errors.As(err, &protectedErr) {
+				t.Fatalf("Expected *ProtectedError, got %T", err)
+			}
+			if protectedErr.Model != "Publisher" || protectedErr.Field != "Contract" || protectedErr.RelatedModel != "Contract" {
+				t.Errorf("Unexpected ProtectedError %+v", protectedErr)
+			}
+			if len(protectedErr.Objects) != 1 || protectedErr.Objects[0].(*Contract).Terms != "Exclusive" {
+				t.Errorf("Expected the contract as blocking object, got %v", protectedErr.Objects)
+			}
+			if !strings.Contains(err.Error(), "Publisher.Contract") {
+				t.Errorf("Expected error to name the association, got %q", err.Error())
+			}
+
+			// Nothing was deleted, not even cascaded records
+			if live, _ := countModels(t, accessor, &Publisher{}); live != 2 {
+				t.Errorf("Expected 2 publishers, got %d", live)
+			}
+			if live, _ := countModels(t, accessor, &Book{}); live != 4 {
+				t.Errorf("Expected 4 books, got %d", live)
+			}
+			if live, _ := countModels(t, accessor, &Editor{}); live != 2 {
+				t.Errorf("Expected 2 editors, got %d", live)
+			}
+		})
+	}
+
+	// Once the contract is gone the publisher can be deleted
+	if err := accessor.Delete(contract); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := accessor.Delete(publishers[0]); err != nil {
+		t.Errorf("Expected delete to succeed, got %v", err)
+	}
+}
+
+// TestQuerySet_DeleteCascade tests on_delete actions on bulk deletes
+func TestQuerySet_DeleteCascade(t *testing.T) {
+	accessor, _ := setupPublishers(t)
+
+	deleted, err := accessor.Objects(&Publisher{}).Filter(Q{"name": "Globex"}).Delete()
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 publisher deleted, got %d", deleted)
+	}
+	if live, _ := countModels(t, accessor, &Chapter{}); live != 3 {
+		t.Errorf("Expected 3 live chapters, got %d", live)
+	}
+
+	deleted, err = accessor.DeleteWhere(&Publisher{}, map[string]interface{}{"name__in": []string{"Acme", "Globex"}})
+	if err != nil {
+		t.Fatalf("DeleteWhere failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 publisher deleted, got %d", deleted)
+	}
+	if live, _ := countModels(t, accessor, &Book{}); live != 0 {
+		t.Errorf("Expected no live books, got %d", live)
+	}
+
+	// Emptying the trash removes the soft-deleted children as well
+	removed, err := accessor.PurgeDeleted(&Publisher{}, 0)
+	if err != nil {
+		t.Fatalf("PurgeDeleted failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 publishers purged, got %d", removed)
+	}
+	if _, all := countModels(t, accessor, &Chapter{}); all != 0 {
+		t.Errorf("Expected all chapters to be purged, got %d", all)
+	}
+	if _, all := countModels(t, accessor, &Editor{}); all != 2 {
+		t.Errorf("Expected editors to be kept, got %d", all)
+	}
+}
+
+// TestOnDeleteRelations_Invalid tests validation of on_delete settings
+func TestOnDeleteRelations_Invalid(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&InvalidOnDelete{}, &NonNullSetNull{}, &Chapter{}, &Review{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		model         interface{}
+		expectedError string
+	}{
+		{name: "Unknown action", model: &InvalidOnDelete{}, expectedError: "invalid on_delete"},
+		{name: "SET_NULL on non-nullable key", model: &NonNullSetNull{}, expectedError: "nullable foreign key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := accessor.Create(tt.model); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			err := accessor.Delete(tt.model)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/AIGamer28100/gobase"
@@ -22,6 +25,19 @@ func main() {
 		email              = flag.String("email", "", "Email for the superuser")
 		password           = flag.String("password", "", "Password for the superuser (if not provided, will be prompted)")
 		jsonFiles          = flag.String("files", "", "Comma-separated list of JSON files for preloading")
+		showMigrationsCmd  = flag.Bool("showmigrations", false, "List applied and pending migrations")
+		sqlMigrateCmd      = flag.String("sqlmigrate", "", "Print the SQL of the named migration without running it")
+		migrationsDir      = flag.String("dir", "migrations", "Directory containing migration files")
+		target             = flag.String("target", "", "Migration to migrate to (\"zero\" unapplies all migrations)")
+		fake               = flag.Bool("fake", false, "Mark migrations as applied or unapplied without running them")
+		backwards          = flag.Bool("backwards", false, "Print the SQL unapplying the migration (with -sqlmigrate)")
+		inspectDBCmd       = flag.Bool("inspectdb", false, "Print Go models generated from the database's tables")
+		tables             = flag.String("tables", "", "Comma-separated list of tables to inspect (with -inspectdb)")
+		packageName        = flag.String("package", "models", "Package of the generated models (with -inspectdb)")
+		checkCmd           = flag.Bool("check", false, "Run checks on the database, exiting non-zero on problems")
+		schemaCheck        = flag.Bool("schema", false, "Check the database schema against the models registered in this binary (with -check)")
+		jsonOutput         = flag.Bool("json", false, "Print the check report as JSON (with -check)")
+		lockTimeout        = flag.Duration("locktimeout", gobase.DefaultMigrationLockTimeout, "How long to wait for another process's migration lock (0 skips migrating if locked)")
 		versionFlag        = flag.Bool("version", false, "Show version information")
 	)
 	flag.Parse()
@@ -34,7 +50,25 @@ func main() {
 	}
 
 	// If no commands are provided, show help
-	if !*migrateCmd && !*createSuperuserCmd && !*preloadCmd {
+	// sqlmigrate only reads migration files, and its output can be piped
+	if *sqlMigrateCmd != "" {
+		handleSQLMigrate(*migrationsDir, *sqlMigrateCmd, *backwards)
+		return
+	}
+
+	// inspectdb output is Go source meant to be redirected to a file
+	if *inspectDBCmd {
+		handleInspectDB(*tables, *packageName)
+		return
+	}
+
+	// check reports are meant for CI, so they are printed on their own
+	if *checkCmd {
+		handleCheck(*schemaCheck, *jsonOutput)
+		return
+	}
+
+	if !*migrateCmd && !*createSuperuserCmd && !*preloadCmd && !*showMigrationsCmd {
 		printHelp()
 		return
 	}
@@ -60,7 +94,17 @@ func main() {
 
 	// Handle commands
 	if *migrateCmd {
-		handleMigrate(accessor)
+		explicitDir := false
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "dir" {
+				explicitDir = true
+			}
+		})
+		handleMigrate(accessor.WithMigrationLockTimeout(*lockTimeout), *migrationsDir, explicitDir, *target, *fake)
+	}
+
+	if *showMigrationsCmd {
+		handleShowMigrations(accessor, *migrationsDir)
 	}
 
 	if *createSuperuserCmd {
@@ -80,11 +124,26 @@ func printHelp() {
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  -migrate              Run database migration")
+	fmt.Println("  -showmigrations       List applied and pending migrations")
+	fmt.Println("  -sqlmigrate string    Print the SQL of a migration without running it")
+	fmt.Println("  -inspectdb            Print Go models generated from the database's tables")
+	fmt.Println("  -check                Run checks on the database, exiting non-zero on problems")
 	fmt.Println("  -createsuperuser      Create a superuser")
 	fmt.Println("  -preload              Preload data from JSON files")
 	fmt.Println("  -version              Show version information")
 	fmt.Println()
 	fmt.Println("Options:")
+	fmt.Println("  -dir string           Directory containing migration files (default \"migrations\");")
+	fmt.Println("                        -migrate applies them when given, or when the default directory has .sql files")
+	fmt.Println("  -target string        Migration to migrate to; \"zero\" unapplies all migrations")
+	fmt.Println("  -fake                 Record migrations as applied or unapplied without running them")
+	fmt.Println("  -backwards            Print the SQL unapplying the migration (with -sqlmigrate)")
+	fmt.Println("  -locktimeout duration How long to wait for another process's migration lock (default 5m)")
+	fmt.Println("  -tables string        Comma-separated list of tables to inspect (default all)")
+	fmt.Println("  -package string       Package of the generated models (default \"models\")")
+	fmt.Println("  -schema               Check the schema against the models registered in this binary (with -check);")
+	fmt.Println("                        the stock binary only registers User, so build one that registers your models")
+	fmt.Println("  -json                 Print the check report as JSON (with -check)")
 	fmt.Println("  -username string      Username for the superuser")
 	fmt.Println("  -email string         Email for the superuser")
 	fmt.Println("  -password string      Password for the superuser")
@@ -92,26 +151,172 @@ func printHelp() {
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  gobase -migrate")
+	fmt.Println("  gobase -migrate -target 0002_add_article_views")
+	fmt.Println("  gobase -migrate -target 0001_initial -fake")
+	fmt.Println("  gobase -showmigrations")
+	fmt.Println("  gobase -sqlmigrate 0002_add_article_views -backwards")
+	fmt.Println("  gobase -inspectdb -tables users,orders > models/models.go")
+	fmt.Println("  gobase -check -schema -json")
 	fmt.Println("  gobase -createsuperuser -username admin -email admin@example.com")
 	fmt.Println("  gobase -preload -files articles.json,users.json")
 	fmt.Println()
 	fmt.Println("Use -help for detailed flag information")
 }
 
-func handleMigrate(accessor *gobase.Accessor) {
+func handleMigrate(accessor *gobase.Accessor, dir string, explicitDir bool, target string, fake bool) {
 	fmt.Println()
 	fmt.Println("=== Running Database Migration ===")
 	fmt.Println()
 
-	// Migrate with automatic model registration (includes User model)
-	err := accessor.Migrate()
+	// Versioned migrations only run when asked for, or when the default
+	// directory holds migration files, not whenever a migrations directory
+	// happens to exist
+	if !explicitDir && target == "" && !fake && !hasMigrationFiles(dir) {
+		// No migration files: migrate with automatic model registration (includes User model)
+		fmt.Printf("Mode: automatic migration of the registered models (no migration files in %s)\n", dir)
+		err := accessor.Migrate()
+		if errors.Is(err, gobase.ErrMigrationLocked) {
+			fmt.Println("Another process is migrating the database, skipping")
+			return
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+
+		fmt.Println("✓ Database migration completed successfully")
+		return
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		log.Fatalf("Migration directory %s does not exist", dir)
+	}
+	fmt.Printf("Mode: versioned migrations from %s\n", dir)
+
+	migrations := loadMigrations(dir)
+	steps, err := accessor.MigrateTo(migrations, gobase.MigrateOptions{Target: target, Fake: fake})
+	for _, step := range steps {
+		action := "Applied"
+		if step.Backwards {
+			action = "Unapplied"
+		}
+		if fake {
+			action += " (fake)"
+		}
+		fmt.Printf("  %s %s\n", action, step.Migration.Name)
+	}
+	if errors.Is(err, gobase.ErrMigrationLocked) {
+		fmt.Println("Another process is migrating the database, skipping")
+		return
+	}
 	if err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
 
+	if len(steps) == 0 {
+		fmt.Println("No migrations to apply")
+	}
 	fmt.Println("✓ Database migration completed successfully")
 }
 
+func handleShowMigrations(accessor *gobase.Accessor, dir string) {
+	fmt.Println()
+	fmt.Println("=== Migrations ===")
+	fmt.Println()
+
+	statuses, err := accessor.ShowMigrations(loadMigrations(dir))
+	if err != nil {
+		log.Fatalf("Failed to list migrations: %v", err)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("(no migrations)")
+	}
+	for _, status := range statuses {
+		mark := " "
+		if status.Applied {
+			mark = "X"
+		}
+		fmt.Printf(" [%s] %s\n", mark, status.Name)
+	}
+}
+
+func handleSQLMigrate(dir, name string, backwards bool) {
+	for _, migration := range loadMigrations(dir) {
+		if migration.Name == name {
+			fmt.Print(migration.SQL(backwards))
+			return
+		}
+	}
+	log.Fatalf("Unknown migration %s", name)
+}
+
+func handleInspectDB(tables, packageName string) {
+	connection, err := gobase.InitDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer connection.Close()
+
+	var names []string
+	if tables != "" {
+		for _, name := range strings.Split(tables, ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+
+	source, err := gobase.NewAccessor(connection).InspectDB(gobase.InspectDBOptions{Package: packageName, Tables: names})
+	if err != nil {
+		log.Fatalf("Failed to inspect database: %v", err)
+	}
+	os.Stdout.Write(source)
+}
+
+func handleCheck(schema, jsonOutput bool) {
+	if !schema {
+		log.Fatal("No check selected. Use -schema to check the database schema")
+	}
+
+	connection, err := gobase.InitDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer connection.Close()
+
+	report, err := gobase.NewAccessor(connection).CheckSchema()
+	if err != nil {
+		log.Fatalf("Schema check failed: %v", err)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(report)
+	}
+
+	if report.HasDrift() {
+		connection.Close()
+		os.Exit(1)
+	}
+}
+
+// hasMigrationFiles reports whether dir contains SQL migration files.
+func hasMigrationFiles(dir string) bool {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	return err == nil && len(files) > 0
+}
+
+func loadMigrations(dir string) []gobase.Migration {
+	migrations, err := gobase.LoadMigrations(os.DirFS(dir))
+	if err != nil {
+		log.Fatalf("Failed to load migrations from %s: %v", dir, err)
+	}
+	return migrations
+}
+
 func handleCreateSuperuser(accessor *gobase.Accessor, username, email, password string) {
 	fmt.Println()
 	fmt.Println("=== Creating Superuser ===")
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -27,38 +28,61 @@ type DatabaseConfig struct {
 	Port     int
 }
 
-// Connection represents a database connection that can be either GORM or MongoDB
+// Connection represents a database connection. Its Backend implements the
+// Accessor's operations; GormDB, or MongoDB and MongoClient, expose the
+// underlying handle of the built-in backends.
 type Connection struct {
 	Type        string
 	GormDB      *gorm.DB
 	MongoDB     *mongo.Database
 	MongoClient *mongo.Client
+	Backend     Backend
+}
+
+// NewConnection returns a connection of the given database type using
+// backend, e.g. for a backend opened outside InitDBWithConfig.
+func NewConnection(dbType string, backend Backend) *Connection {
+	connection := &Connection{Type: dbType, Backend: backend}
+	switch b := backend.(type) {
+	case *gormBackend:
+		connection.GormDB = b.db
+	case *mongoBackend:
+		connection.MongoDB = b.database
+		connection.MongoClient = b.client
+	}
+	return connection
+}
+
+// backend returns the connection's Backend. Connections built without one
+// use the backend of their GORM or MongoDB handle.
+func (c *Connection) backend() Backend {
+	switch {
+	case c.Backend != nil:
+		return c.Backend
+	case c.GormDB != nil:
+		return &gormBackend{db: c.GormDB}
+	case c.MongoDB != nil:
+		return &mongoBackend{client: c.MongoClient, database: c.MongoDB}
+	}
+	return nil
 }
 
 // GetDB returns the underlying database connection
 func (c *Connection) GetDB() interface{} {
-	if c.Type == mongoDBType {
+	if c.MongoDB != nil {
 		return c.MongoDB
 	}
-	return c.GormDB
+	if c.GormDB != nil {
+		return c.GormDB
+	}
+	return c.Backend
 }
 
 // Close closes the database connection
 func (c *Connection) Close() error {
-	if c.Type == mongoDBType && c.MongoClient != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		return c.MongoClient.Disconnect(ctx)
-	}
-
-	if c.GormDB != nil {
-		sqlDB, err := c.GormDB.DB()
-		if err != nil {
-			return err
-		}
-		return sqlDB.Close()
+	if backend := c.backend(); backend != nil {
+		return backend.Close()
 	}
-
 	return nil
 }
 
@@ -90,7 +114,8 @@ func LoadConfig() (*DatabaseConfig, error) {
 		return nil, errors.New("DB_TYPE is required")
 	}
 
-	if config.Name == "" {
+	// In-memory databases have no name
+	if config.Name == "" && config.Type != memoryDBType {
 		return nil, errors.New("DB_NAME is required")
 	}
 
@@ -117,7 +142,10 @@ func LoadConfig() (*DatabaseConfig, error) {
 			config.Port = 27017
 		}
 	default:
-		return nil, fmt.Errorf("unsupported database type: %s. Supported types: postgres, sqlite, mongodb", config.Type)
+		if _, ok := lookupBackend(config.Type); !ok {
+			return nil, fmt.Errorf("unsupported database type: %s. Supported types: %s",
+				config.Type, strings.Join(Backends(), ", "))
+		}
 	}
 
 	return config, nil
@@ -136,21 +164,30 @@ func InitDB() (*Connection, error) {
 }
 
 // InitDBWithConfig establishes a connection using the provided configuration
+// and the backend registered for its type.
 func InitDBWithConfig(config *DatabaseConfig) (*Connection, error) {
-	switch config.Type {
-	case "postgres":
-		return initPostgreSQL(config)
-	case "sqlite":
-		return initSQLite(config)
-	case mongoDBType:
-		return initMongoDB(config)
-	default:
+	factory, ok := lookupBackend(config.Type)
+	if !ok {
 		return nil, fmt.Errorf("unsupported database type: %s", config.Type)
 	}
+
+	backend, err := factory(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewConnection(config.Type, backend), nil
+}
+
+// init registers the built-in backends.
+func init() {
+	RegisterBackend("postgres", initPostgreSQL)
+	RegisterBackend("sqlite", initSQLite)
+	RegisterBackend(mongoDBType, initMongoDB)
+	RegisterBackend(memoryDBType, initMemory)
 }
 
 // initPostgreSQL initializes a PostgreSQL connection
-func initPostgreSQL(config *DatabaseConfig) (*Connection, error) {
+func initPostgreSQL(config *DatabaseConfig) (Backend, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=UTC",
 		config.Host, config.User, config.Password, config.Name, config.Port)
 
@@ -161,14 +198,11 @@ func initPostgreSQL(config *DatabaseConfig) (*Connection, error) {
 		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
 
-	return &Connection{
-		Type:   "postgres",
-		GormDB: db,
-	}, nil
+	return NewGormBackend(db), nil
 }
 
 // initSQLite initializes a SQLite connection
-func initSQLite(config *DatabaseConfig) (*Connection, error) {
+func initSQLite(config *DatabaseConfig) (Backend, error) {
 	db, err := gorm.Open(sqlite.Open(config.Name), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
@@ -176,14 +210,11 @@ func initSQLite(config *DatabaseConfig) (*Connection, error) {
 		return nil, fmt.Errorf("failed to connect to SQLite: %w", err)
 	}
 
-	return &Connection{
-		Type:   "sqlite",
-		GormDB: db,
-	}, nil
+	return NewGormBackend(db), nil
 }
 
 // initMongoDB initializes a MongoDB connection
-func initMongoDB(config *DatabaseConfig) (*Connection, error) {
+func initMongoDB(config *DatabaseConfig) (Backend, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -203,11 +234,5 @@ func initMongoDB(config *DatabaseConfig) (*Connection, error) {
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
-	database := client.Database(config.Name)
-
-	return &Connection{
-		Type:        mongoDBType,
-		MongoDB:     database,
-		MongoClient: client,
-	}, nil
+	return &mongoBackend{client: client, database: client.Database(config.Name)}, nil
 }
@@ -0,0 +1,244 @@
+package gobase
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+// Sentinel errors returned by Accessor methods regardless of the underlying
+// database, so callers can use errors.Is instead of matching driver errors.
+var (
+	// ErrDoesNotExist is returned when a lookup matches no record.
+	// It also matches gorm.ErrRecordNotFound for backwards compatibility.
+	ErrDoesNotExist = errors.New("object does not exist")
+
+	// ErrMultipleObjectsReturned is returned when a lookup expected to
+	// match a single record matches several.
+	ErrMultipleObjectsReturned = errors.New("multiple objects returned")
+
+	// ErrUniqueViolation is returned when a write violates a unique
+	// constraint or primary key.
+	ErrUniqueViolation = errors.New("unique constraint violation")
+
+	// ErrForeignKeyViolation is returned when a write violates a foreign
+	// key constraint.
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+
+	// ErrNotNullViolation is returned when a write leaves a NOT NULL
+	// column empty.
+	ErrNotNullViolation = errors.New("not null constraint violation")
+
+	// ErrInvalidPage is returned by Paginate for a page number or page
+	// size outside the valid range.
+	ErrInvalidPage = errors.New("invalid page")
+
+	// ErrInvalidCursor is returned by CursorPaginate for a cursor token
+	// that cannot be decoded.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrProtected is returned when a delete is blocked by related records
+	// of an association declared with on_delete:PROTECT. The concrete error
+	// is a *ProtectedError listing the blocking records.
+	ErrProtected = errors.New("protected by related objects")
+
+	// ErrMigrationLocked is returned by Migrate and MigrateTo when another
+	// process holds the migration lock beyond the lock timeout.
+	ErrMigrationLocked = errors.New("migrations are locked by another process")
+
+	// ErrSchemaMigrationPending is returned by Migrate when a registered
+	// migration with Up statements is pending ahead of data migrations.
+	// Such migrations are applied with MigrateTo.
+	ErrSchemaMigrationPending = errors.New("schema migration pending")
+
+	// ErrNotSupported is returned by operations the connection's backend
+	// does not provide, such as QuerySets on backends that are not a
+	// QueryCompiler and migration files on backends that are not a
+	// Migrator.
+	ErrNotSupported = errors.New("operation not supported")
+)
+
+// PostgreSQL error codes for integrity constraint violations.
+const (
+	pgNotNullViolation    = "23502"
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+)
+
+// pgKeyDetail extracts the column list from a PostgreSQL error detail such
+// as `Key (username)=(admin) already exists.`
+var pgKeyDetail = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// mongoDuplicateKey extracts the namespace, index and keys from a MongoDB
+// duplicate key error message.
+var mongoDuplicateKey = regexp.MustCompile(`collection: (\S+) index: (\S+) dup key: \{(.*)\}`)
+
+// mongoDuplicateKeyField extracts the field names from the keys of a
+// MongoDB duplicate key error message.
+var mongoDuplicateKeyField = regexp.MustCompile(`(?:^|,)\s*([A-Za-z0-9_.]+):`)
+
+// IntegrityError describes a constraint violation reported by the database.
+// errors.Is matches it against its Kind, e.g. ErrUniqueViolation.
+type IntegrityError struct {
+	Kind       error  // ErrUniqueViolation, ErrForeignKeyViolation or ErrNotNullViolation
+	Table      string // table name, when reported by the database
+	Field      string // offending column(s), when reported by the database
+	Constraint string // constraint or index name, when reported by the database
+	Err        error  // original driver error
+}
+
+// Error implements the error interface.
+func (e *IntegrityError) Error() string {
+	var details []string
+	if e.Constraint != "" {
+		details = append(details, "constraint "+e.Constraint)
+	}
+	if e.Field != "" {
+		field := e.Field
+		if e.Table != "" {
+			field = e.Table + "." + e.Field
+		}
+		details = append(details, "field "+field)
+	}
+
+	if len(details) == 0 {
+		return e.Kind.Error()
+	}
+	return fmt.Sprintf("%s (%s)", e.Kind, strings.Join(details, ", "))
+}
+
+// Is reports whether target is the kind of this violation.
+func (e *IntegrityError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap returns the original driver error.
+func (e *IntegrityError) Unwrap() error {
+	return e.Err
+}
+
+// translateError normalizes database errors into the package's sentinel
+// errors. Errors that are not recognized are returned unchanged.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Already translated
+	var integrityErr *IntegrityError
+	if errors.As(err, &integrityErr) {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, ErrDoesNotExist) {
+		return fmt.Errorf("%w: %w", ErrDoesNotExist, err)
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return translateSQLiteError(sqliteErr, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return translatePostgresError(pgErr, err)
+	}
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: %w", ErrDoesNotExist, err)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return translateMongoDuplicateKeyError(err)
+	}
+
+	return err
+}
+
+// translateMongoDuplicateKeyError converts a MongoDB duplicate key error.
+// MongoDB reports the index and its keys in the message, e.g.
+// `E11000 duplicate key error collection: app.users index: username_1 dup key: { username: "admin" }`.
+func translateMongoDuplicateKeyError(err error) error {
+	integrityErr := &IntegrityError{Kind: ErrUniqueViolation, Err: err}
+	if match := mongoDuplicateKey.FindStringSubmatch(err.Error()); match != nil {
+		_, integrityErr.Table, _ = strings.Cut(match[1], ".")
+		integrityErr.Constraint = match[2]
+		var fields []string
+		for _, key := range mongoDuplicateKeyField.FindAllStringSubmatch(match[3], -1) {
+			fields = append(fields, key[1])
+		}
+		integrityErr.Field = strings.Join(fields, ",")
+	}
+	return integrityErr
+}
+
+// translateSQLiteError converts SQLite constraint errors. SQLite reports
+// the offending columns in the message, e.g.
+// "UNIQUE constraint failed: users.username".
+func translateSQLiteError(sqliteErr sqlite3.Error, err error) error {
+	var kind error
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		kind = ErrUniqueViolation
+	case sqlite3.ErrConstraintForeignKey:
+		kind = ErrForeignKeyViolation
+	case sqlite3.ErrConstraintNotNull:
+		kind = ErrNotNullViolation
+	default:
+		return err
+	}
+
+	integrityErr := &IntegrityError{Kind: kind, Err: err}
+
+	_, columns, found := strings.Cut(sqliteErr.Error(), "constraint failed: ")
+	if found {
+		var fields []string
+		for _, column := range strings.Split(columns, ",") {
+			table, field, ok := strings.Cut(strings.TrimSpace(column), ".")
+			if !ok {
+				continue
+			}
+			integrityErr.Table = table
+			fields = append(fields, field)
+		}
+		integrityErr.Field = strings.Join(fields, ",")
+	}
+
+	return integrityErr
+}
+
+// translatePostgresError converts PostgreSQL integrity constraint errors.
+func translatePostgresError(pgErr *pgconn.PgError, err error) error {
+	var kind error
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		kind = ErrUniqueViolation
+	case pgForeignKeyViolation:
+		kind = ErrForeignKeyViolation
+	case pgNotNullViolation:
+		kind = ErrNotNullViolation
+	default:
+		return err
+	}
+
+	integrityErr := &IntegrityError{
+		Kind:       kind,
+		Table:      pgErr.TableName,
+		Field:      pgErr.ColumnName,
+		Constraint: pgErr.ConstraintName,
+		Err:        err,
+	}
+
+	if integrityErr.Field == "" {
+		if match := pgKeyDetail.FindStringSubmatch(pgErr.Detail); match != nil {
+			integrityErr.Field = strings.ReplaceAll(match[1], " ", "")
+		}
+	}
+
+	return integrityErr
+}
@@ -0,0 +1,185 @@
+package gobase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// RequiredFieldModel is a model with a NOT NULL column for constraint tests
+type RequiredFieldModel struct {
+	BaseModel
+	Code *string `gorm:"not null"`
+}
+
+// TestErrors_DoesNotExist tests that missing records map to ErrDoesNotExist
+func TestErrors_DoesNotExist(t *testing.T) {
+	accessor := setupArticles(t)
+
+	article := &Article{}
+	err := accessor.Get(article, 999)
+	if !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Expected ErrDoesNotExist from Get, got %v", err)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected gorm.ErrRecordNotFound to still match, got %v", err)
+	}
+
+	err = accessor.Objects(&Article{}).Filter(Q{"author": "nobody"}).First(article)
+	if !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Expected ErrDoesNotExist from First, got %v", err)
+	}
+}
+
+// TestQuerySet_Get tests fetching exactly one record
+func TestQuerySet_Get(t *testing.T) {
+	accessor := setupArticles(t)
+
+	tests := []struct {
+		name        string
+		filter      Q
+		expectedErr error
+		expected    string
+	}{
+		{name: "Single match", filter: Q{"title": "Advanced Go"}, expected: "Advanced Go"},
+		{name: "No match", filter: Q{"author": "nobody"}, expectedErr: ErrDoesNotExist},
+		{name: "Multiple matches", filter: Q{"author": "alice"}, expectedErr: ErrMultipleObjectsReturned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := &Article{}
+			err := accessor.Objects(&Article{}).Filter(tt.filter).Get(article)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			if article.Title != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, article.Title)
+			}
+		})
+	}
+
+	articles := For[Article](accessor)
+	if _, err := articles.GetBy(Q{"status": "published"}); !errors.Is(err, ErrMultipleObjectsReturned) {
+		t.Errorf("Expected ErrMultipleObjectsReturned from GetBy, got %v", err)
+	}
+}
+
+// TestErrors_UniqueViolation tests that duplicate inserts map to ErrUniqueViolation
+func TestErrors_UniqueViolation(t *testing.T) {
+	connection := setupTestDB(t)
+	accessor := NewAccessor(connection)
+
+	if err := accessor.Migrate(&User{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	if err := accessor.Create(&User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	err := accessor.Create(&User{Username: "alice", Email: "other@example.com", PasswordHash: "hash"})
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Fatalf("Expected ErrUniqueViolation, got %v", err)
+	}
+
+	var integrityErr *IntegrityError
+	if !errors.As(err, &integrityErr) {
+		t.Fatalf("Expected *IntegrityError, got %T", err)
+	}
+	if integrityErr.Table != "users" {
+		t.Errorf("Expected table 'users', got %s", integrityErr.Table)
+	}
+	if integrityErr.Field != "username" {
+		t.Errorf("Expected field 'username', got %s", integrityErr.Field)
+	}
+	if integrityErr.Err == nil {
+		t.Error("Expected original driver error to be preserved")
+	}
+}
+
+// TestErrors_NotNullViolation tests that missing required values map to ErrNotNullViolation
+func TestErrors_NotNullViolation(t *testing.T) {
+	connection := setupTestDB(t)
+	accessor := NewAccessor(connection)
+
+	if err := accessor.Migrate(&RequiredFieldModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	err := accessor.Create(&RequiredFieldModel{})
+	if !errors.Is(err, ErrNotNullViolation) {
+		t.Fatalf("Expected ErrNotNullViolation, got %v", err)
+	}
+
+	var integrityErr *IntegrityError
+	if errors.As(err, &integrityErr) && integrityErr.Field != "code" {
+		t.Errorf("Expected field 'code', got %s", integrityErr.Field)
+	}
+}
+
+// TestErrors_TranslatePostgres tests the PostgreSQL error code mapping
+func TestErrors_TranslatePostgres(t *testing.T) {
+	tests := []struct {
+		name          string
+		pgErr         *pgconn.PgError
+		expectedErr   error
+		expectedField string
+	}{
+		{
+			name:          "Unique violation from detail",
+			pgErr:         &pgconn.PgError{Code: "23505", TableName: "users", ConstraintName: "idx_users_username", Detail: "Key (username)=(alice) already exists."},
+			expectedErr:   ErrUniqueViolation,
+			expectedField: "username",
+		},
+		{
+			name:          "Foreign key violation",
+			pgErr:         &pgconn.PgError{Code: "23503", TableName: "articles", Detail: "Key (author_id)=(7) is not present in table \"users\"."},
+			expectedErr:   ErrForeignKeyViolation,
+			expectedField: "author_id",
+		},
+		{
+			name:          "Not null violation",
+			pgErr:         &pgconn.PgError{Code: "23502", TableName: "users", ColumnName: "email"},
+			expectedErr:   ErrNotNullViolation,
+			expectedField: "email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.pgErr)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+			}
+
+			var integrityErr *IntegrityError
+			if !errors.As(err, &integrityErr) {
+				t.Fatalf("Expected *IntegrityError, got %T", err)
+			}
+			if integrityErr.Field != tt.expectedField {
+				t.Errorf("Expected field %s, got %s", tt.expectedField, integrityErr.Field)
+			}
+
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) {
+				t.Error("Expected original *pgconn.PgError to be unwrappable")
+			}
+		})
+	}
+
+	// Unrelated errors pass through untouched
+	other := &pgconn.PgError{Code: "42P01"}
+	if err := translateError(other); err != other {
+		t.Errorf("Expected unrelated error to be returned unchanged, got %v", err)
+	}
+}
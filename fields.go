@@ -0,0 +1,58 @@
+package gobase
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// FieldError is returned when a query references a field that does not
+// exist on the model. Field names are never interpolated into SQL unless
+// they resolve to a column of the model's schema.
+type FieldError struct {
+	Field string
+	Model string
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("unknown field '%s' on model '%s'", e.Field, e.Model)
+}
+
+// modelSchema parses the GORM schema of the given model using the naming
+// strategy and schema cache of the backend's GORM session.
+func (a *Accessor) modelSchema(model interface{}) (*schema.Schema, error) {
+	if _, ok := a.queryCompiler(); ok {
+		return gormSchema(a.db(), model)
+	}
+	if err := a.requireMigrator("schema parsing"); err != nil {
+		return nil, err
+	}
+	return gormSchema(a.migrationDB(), model)
+}
+
+// gormSchema parses the GORM schema of the given model using the naming
+// strategy and schema cache of db.
+func gormSchema(db *gorm.DB, model interface{}) (*schema.Schema, error) {
+	if model == nil {
+		return nil, errors.New("model cannot be nil")
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("failed to parse model schema: %w", err)
+	}
+	return stmt.Schema, nil
+}
+
+// resolveField resolves a struct field name or column name against the
+// schema and returns the database column name.
+func resolveField(s *schema.Schema, name string) (string, error) {
+	field := s.LookUpField(name)
+	if field == nil || field.DBName == "" {
+		return "", &FieldError{Field: name, Model: s.Name}
+	}
+	return field.DBName, nil
+}
@@ -0,0 +1,153 @@
+package gobase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// getOrCreateAttempts bounds how often GetOrCreate and UpdateOrCreate look
+// the record up again after losing an insert race.
+const getOrCreateAttempts = 3
+
+// GetOrCreate looks up the single record matching lookup and populates
+// model with it, creating it first if it does not exist, like Django's
+// get_or_create. It reports whether the record was created.
+//
+// A new record is built from the exact-match lookups (keys without a
+// lookup operator) and defaults; any values already on model are
+// discarded. The insert uses ON CONFLICT DO NOTHING, so when a concurrent
+// caller creates the same record first, the record is looked up again
+// instead of failing. If the insert conflicts with a record that does not
+// match lookup, e.g. a soft-deleted one, an error matching
+// ErrUniqueViolation is returned.
+//
+//	article := &Article{}
+//	created, err := accessor.GetOrCreate(article,
+//		map[string]interface{}{"slug": "go-basics"},
+//		map[string]interface{}{"title": "Go Basics"})
+func (a *Accessor) GetOrCreate(model interface{}, lookup, defaults map[string]interface{}) (bool, error) {
+	return a.getOrCreate(model, lookup, defaults, false)
+}
+
+// UpdateOrCreate looks up the single record matching lookup and updates it
+// with defaults, creating it from lookup and defaults if it does not exist,
+// like Django's update_or_create. model is populated with the resulting
+// record, and the returned bool reports whether it was created.
+//
+// On PostgreSQL the existing record is locked with SELECT ... FOR UPDATE
+// until the update is committed. Conflicting inserts are handled as in
+// GetOrCreate.
+func (a *Accessor) UpdateOrCreate(model interface{}, lookup, defaults map[string]interface{}) (bool, error) {
+	return a.getOrCreate(model, lookup, defaults, true)
+}
+
+// getOrCreate implements GetOrCreate and UpdateOrCreate.
+func (a *Accessor) getOrCreate(model interface{}, lookup, defaults map[string]interface{}, update bool) (bool, error) {
+	if err := a.ValidateModel(model); err != nil {
+		return false, fmt.Errorf("model validation failed: %w", err)
+	}
+	if len(lookup) == 0 {
+		return false, errors.New("lookup cannot be empty")
+	}
+
+	// Only supported by QueryCompiler backends
+	if err := a.requireQueryCompiler("GetOrCreate"); err != nil {
+		return false, err
+	}
+
+	s, err := a.modelSchema(model)
+	if err != nil {
+		return false, err
+	}
+
+	record := reflect.ValueOf(model).Elem()
+	created := false
+	err = a.Transaction(func(tx *Accessor) error {
+		for attempt := 0; attempt < getOrCreateAttempts; attempt++ {
+			qs := tx.Objects(model).Filter(Q(lookup))
+			qs.forUpdate = update
+
+			err := qs.Get(model)
+			if err == nil {
+				if update && len(defaults) > 0 {
+					return tx.applyDefaults(s, model, defaults)
+				}
+				return nil
+			}
+			if !errors.Is(err, ErrDoesNotExist) {
+				return err
+			}
+
+			record.Set(reflect.Zero(record.Type()))
+			if err := assignFields(tx.Context(), s, record, lookup, defaults); err != nil {
+				return err
+			}
+
+			result := tx.db().Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+			if result.Error != nil {
+				return translateError(result.Error)
+			}
+			if result.RowsAffected > 0 {
+				created = true
+				return nil
+			}
+
+			// Another transaction inserted a conflicting record first
+		}
+
+		return fmt.Errorf("%w: %s conflicts with an existing record not matching the lookup", ErrUniqueViolation, s.Table)
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// applyDefaults sets defaults on an existing record and saves those fields.
+func (a *Accessor) applyDefaults(s *schema.Schema, model interface{}, defaults map[string]interface{}) error {
+	columns := make([]string, 0, len(defaults))
+	for name := range defaults {
+		column, err := resolveField(s, name)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, column)
+	}
+
+	if err := assignFields(a.Context(), s, reflect.ValueOf(model).Elem(), defaults); err != nil {
+		return err
+	}
+
+	result := a.db().Model(model).Select(columns).Updates(model)
+	return translateError(result.Error)
+}
+
+// assignFields sets the exact-match lookups and defaults on record, in
+// order, so that defaults take precedence.
+func assignFields(ctx context.Context, s *schema.Schema, record reflect.Value, values ...map[string]interface{}) error {
+	for _, fields := range values {
+		for key, value := range fields {
+			l, err := parseLookup(key)
+			if err != nil {
+				return err
+			}
+			if l.transform != "" || l.operator != LookupExact {
+				continue
+			}
+
+			field := s.LookUpField(l.field)
+			if field == nil || field.DBName == "" {
+				return &FieldError{Field: l.field, Model: s.Name}
+			}
+			if err := field.Set(ctx, record, value); err != nil {
+				return fmt.Errorf("failed to set field '%s': %w", l.field, err)
+			}
+		}
+	}
+	return nil
+}
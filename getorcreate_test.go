@@ -0,0 +1,209 @@
+package gobase
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestAccessor_GetOrCreate tests fetching existing records and creating missing ones
+func TestAccessor_GetOrCreate(t *testing.T) {
+	accessor := setupArticles(t)
+
+	tests := []struct {
+		name            string
+		lookup          map[string]interface{}
+		defaults        map[string]interface{}
+		expectedCreated bool
+		expectedTitle   string
+		expectedAuthor  string
+		expectedViews   int
+	}{
+		{
+			name:           "Existing record ignores defaults",
+			lookup:         map[string]interface{}{"title": "Go Basics"},
+			defaults:       map[string]interface{}{"views": 99},
+			expectedTitle:  "Go Basics",
+			expectedAuthor: "alice",
+			expectedViews:  10,
+		},
+		{
+			name:            "Missing record is created from lookup and defaults",
+			lookup:          map[string]interface{}{"title": "Rust Basics", "views__gte": 5},
+			defaults:        map[string]interface{}{"author": "dave", "Status": "draft"},
+			expectedCreated: true,
+			expectedTitle:   "Rust Basics",
+			expectedAuthor:  "dave",
+		},
+		{
+			name:           "Created record is found afterwards",
+			lookup:         map[string]interface{}{"title": "Rust Basics"},
+			defaults:       map[string]interface{}{"author": "erin"},
+			expectedTitle:  "Rust Basics",
+			expectedAuthor: "dave",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := &Article{Author: "stale"}
+			created, err := accessor.GetOrCreate(article, tt.lookup, tt.defaults)
+			if err != nil {
+				t.Fatalf("GetOrCreate failed: %v", err)
+			}
+
+			if created != tt.expectedCreated {
+				t.Errorf("Expected created=%v, got %v", tt.expectedCreated, created)
+			}
+			if article.ID == 0 {
+				t.Error("Expected ID to be set")
+			}
+			if article.Title != tt.expectedTitle || article.Author != tt.expectedAuthor || article.Views != tt.expectedViews {
+				t.Errorf("Expected %s by %s with %d views, got %s by %s with %d views",
+					tt.expectedTitle, tt.expectedAuthor, tt.expectedViews, article.Title, article.Author, article.Views)
+			}
+		})
+	}
+
+	count, err := accessor.Objects(&Article{}).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("Expected 5 articles, got %d", count)
+	}
+}
+
+// TestAccessor_UpdateOrCreate tests updating existing records and creating missing ones
+func TestAccessor_UpdateOrCreate(t *testing.T) {
+	accessor := setupArticles(t)
+
+	article := &Article{}
+	created, err := accessor.UpdateOrCreate(article, map[string]interface{}{"title": "Advanced Go"}, map[string]interface{}{"views": 75})
+	if err != nil {
+		t.Fatalf("UpdateOrCreate failed: %v", err)
+	}
+	if created {
+		t.Error("Expected existing record to be updated")
+	}
+	if article.Views != 75 || article.Author != "bob" {
+		t.Errorf("Expected 75 views by bob, got %d by %s", article.Views, article.Author)
+	}
+
+	reloaded := &Article{}
+	if err := accessor.Get(reloaded, article.ID); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if reloaded.Views != 75 || reloaded.Status != "published" {
+		t.Errorf("Expected stored record to have 75 views and keep its status, got %d and %s", reloaded.Views, reloaded.Status)
+	}
+
+	created, err = accessor.UpdateOrCreate(&Article{}, map[string]interface{}{"title": "New"}, map[string]interface{}{"views": 1})
+	if err != nil {
+		t.Fatalf("UpdateOrCreate failed: %v", err)
+	}
+	if !created {
+		t.Error("Expected missing record to be created")
+	}
+
+	if _, err := accessor.UpdateOrCreate(&Article{}, map[string]interface{}{"author": "alice"}, nil); !errors.Is(err, ErrMultipleObjectsReturned) {
+		t.Errorf("Expected ErrMultipleObjectsReturned, got %v", err)
+	}
+}
+
+// TestAccessor_GetOrCreateErrors tests invalid arguments and conflicting records
+func TestAccessor_GetOrCreateErrors(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&User{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if err := accessor.Create(&User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	var fieldErr *FieldError
+	if _, err := accessor.GetOrCreate(&User{}, map[string]interface{}{"username": "bob"}, map[string]interface{}{"missing": 1}); !errors.As(err, &fieldErr) {
+		t.Errorf("Expected *FieldError, got %v", err)
+	}
+	if _, err := accessor.GetOrCreate(&User{}, nil, nil); err == nil {
+		t.Error("Expected error for empty lookup")
+	}
+	if _, err := accessor.GetOrCreate(&NotAModel{}, map[string]interface{}{"name": "x"}, nil); err == nil {
+		t.Error("Expected validation error")
+	}
+
+	// The insert conflicts on email with a record the lookup does not match
+	_, err := accessor.GetOrCreate(&User{},
+		map[string]interface{}{"username": "bob"},
+		map[string]interface{}{"email": "alice@example.com", "password_hash": "hash"})
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("Expected ErrUniqueViolation, got %v", err)
+	}
+}
+
+// TestAccessor_GetOrCreateConcurrent tests that concurrent callers create a single record
+func TestAccessor_GetOrCreateConcurrent(t *testing.T) {
+	// A file database shared by several connections; immediate transactions
+	// make SQLite serialize writers instead of failing with SQLITE_BUSY
+	config := &DatabaseConfig{
+		Type: "sqlite",
+		Name: filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000&_txlock=immediate",
+	}
+	connection, err := InitDBWithConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+	accessor := NewAccessor(connection)
+	if err := accessor.Migrate(&User{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	results := make(chan bool, workers)
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, created, err := For[User](accessor).GetOrCreate(
+				map[string]interface{}{"username": "carol"},
+				map[string]interface{}{"email": "carol@example.com", "password_hash": "hash"})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if user.Username != "carol" {
+				errs <- errors.New("unexpected user " + user.Username)
+				return
+			}
+			results <- created
+		}()
+	}
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("GetOrCreate failed: %v", err)
+	}
+
+	createdCount := 0
+	for created := range results {
+		if created {
+			createdCount++
+		}
+	}
+	if createdCount != 1 {
+		t.Errorf("Expected exactly one caller to create the record, got %d", createdCount)
+	}
+
+	count, err := accessor.Objects(&User{}).Filter(Q{"username": "carol"}).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 user, got %d", count)
+	}
+}
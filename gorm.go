@@ -0,0 +1,142 @@
+package gobase
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// gormBackend is the Backend for the SQL databases supported by GORM. It
+// is a QueryCompiler and a Migrator, so features built on SQL, such as
+// QuerySets and versioned migrations, are available on its connections.
+type gormBackend struct {
+	db *gorm.DB
+}
+
+// NewGormBackend returns a Backend for a GORM database, e.g. to register
+// a database type for another GORM dialector with RegisterBackend.
+func NewGormBackend(db *gorm.DB) Backend {
+	return &gormBackend{db: db}
+}
+
+// session returns the GORM handle bound to ctx.
+func (b *gormBackend) session(ctx context.Context) *gorm.DB {
+	return b.db.WithContext(ctx)
+}
+
+// QueryDB implements QueryCompiler.
+func (b *gormBackend) QueryDB(ctx context.Context) *gorm.DB {
+	return b.session(ctx)
+}
+
+// MigrationDB implements Migrator.
+func (b *gormBackend) MigrationDB(ctx context.Context) *gorm.DB {
+	return b.session(ctx)
+}
+
+// read returns the GORM handle for reading model with query. The
+// schema is only parsed when conditions or a soft-delete scope need it.
+func (b *gormBackend) read(ctx context.Context, model interface{}, query Query) (*gorm.DB, error) {
+	db := b.session(ctx)
+	if len(query.Conditions) > 0 || query.Deleted != ExcludeDeleted {
+		s, err := gormSchema(b.db, model)
+		if err != nil {
+			return nil, err
+		}
+		db = applyDeletedScope(db, s, query.Deleted)
+
+		expr, err := compileConditions(db.Dialector.Name(), s, query.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		if expr != nil {
+			db = db.Where(expr)
+		}
+	}
+	if query.Where != "" {
+		db = db.Where(query.Where, query.Args...)
+	}
+	return db, nil
+}
+
+// accessor returns an Accessor on the backend, for the operations
+// implemented with QuerySets such as cascading deletes.
+func (b *gormBackend) accessor(ctx context.Context) *Accessor {
+	return NewAccessor(NewConnection(b.db.Dialector.Name(), b)).WithContext(ctx)
+}
+
+// Create implements Backend.
+func (b *gormBackend) Create(ctx context.Context, model interface{}) error {
+	return translateError(b.session(ctx).Create(model).Error)
+}
+
+// Get implements Backend.
+func (b *gormBackend) Get(ctx context.Context, model interface{}, id interface{}, query Query) error {
+	db, err := b.read(ctx, model, query)
+	if err != nil {
+		return err
+	}
+
+	// Handle both numeric and string IDs properly
+	return translateError(db.Where("id = ?", id).First(model).Error)
+}
+
+// Find implements Backend.
+func (b *gormBackend) Find(ctx context.Context, models interface{}, query Query) error {
+	db, err := b.read(ctx, models, query)
+	if err != nil {
+		return err
+	}
+	return translateError(db.Find(models).Error)
+}
+
+// Count implements Backend.
+func (b *gormBackend) Count(ctx context.Context, model interface{}, query Query) (int64, error) {
+	db, err := b.read(ctx, model, query)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	result := db.Model(model).Count(&count)
+	return count, translateError(result.Error)
+}
+
+// Update implements Backend.
+func (b *gormBackend) Update(ctx context.Context, model interface{}) error {
+	return translateError(b.session(ctx).Save(model).Error)
+}
+
+// Delete implements Backend, applying the on-delete actions of the
+// model's associations.
+func (b *gormBackend) Delete(ctx context.Context, model interface{}) error {
+	return b.accessor(ctx).deleteModel(model, false)
+}
+
+// Migrate implements Backend with GORM's AutoMigrate.
+func (b *gormBackend) Migrate(ctx context.Context, models []interface{}) error {
+	return b.session(ctx).AutoMigrate(models...)
+}
+
+// Transaction implements Backend. Nested transactions use savepoints.
+func (b *gormBackend) Transaction(ctx context.Context, fn func(tx Backend) error) error {
+	return b.session(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormBackend{db: tx})
+	})
+}
+
+// Tables implements Backend.
+func (b *gormBackend) Tables(ctx context.Context) ([]string, error) {
+	tables, err := b.db.Session(&gorm.Session{Context: ctx, Logger: logger.Discard}).Migrator().GetTables()
+	return tables, translateError(err)
+}
+
+// Close implements Backend.
+func (b *gormBackend) Close() error {
+	sqlDB, err := b.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,698 @@
+package gobase
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"go/format"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jinzhu/inflection"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// InspectDBOptions configures InspectDB.
+type InspectDBOptions struct {
+	// Package is the package clause of the generated file. It defaults to
+	// "models".
+	Package string
+	// Tables restricts the inspection to the named tables. It defaults to
+	// every table except gobase's own migration bookkeeping tables.
+	Tables []string
+}
+
+// inspectedTable is the introspected schema of a table.
+type inspectedTable struct {
+	Name        string
+	Columns     []gorm.ColumnType
+	Indexes     []gorm.Index
+	ForeignKeys []foreignKey
+}
+
+// foreignKey is a single-column foreign key constraint. RefColumn is empty
+// when the constraint references the primary key implicitly.
+type foreignKey struct {
+	Constraint string
+	Column     string
+	RefTable   string
+	RefColumn  string
+	OnDelete   string
+}
+
+// baseModelColumns are the columns of the fields of BaseModel.
+var baseModelColumns = []string{"id", "created_at", "updated_at", "deleted_at"}
+
+// goInitialisms are the words written in upper case in Go identifiers.
+var goInitialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "GUID": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true, "SSH": true, "TLS": true,
+	"TTL": true, "UI": true, "UID": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// InspectDB introspects the tables of an existing database and returns the
+// source of a Go file declaring a model for each, like Django's inspectdb.
+// Columns, types, nullability, defaults, primary keys, indexes and foreign
+// keys are carried over into gorm tags; foreign keys to inspected tables
+// also get a belongs-to association field. Tables with id, created_at,
+// updated_at and deleted_at columns embed BaseModel, overriding the fields
+// whose columns do not match it, e.g. a text id. Other tables get plain
+// structs, which the Accessor refuses until the missing columns are added.
+//
+// The generated models are a starting point: review them, especially
+// fields commented as guessed types, before migrating with them.
+//
+//	source, err := accessor.InspectDB(gobase.InspectDBOptions{Tables: []string{"users", "orders"}})
+func (a *Accessor) InspectDB(options InspectDBOptions) ([]byte, error) {
+	// Only supported by Migrator backends
+	if err := a.requireMigrator("InspectDB"); err != nil {
+		return nil, err
+	}
+
+	pkg := options.Package
+	if pkg == "" {
+		pkg = "models"
+	}
+
+	tables, err := a.inspectTables(options.Tables)
+	if err != nil {
+		return nil, err
+	}
+	return inspectDBSource(tables, pkg, a.migrationDB().Dialector.Name(), a.migrationDB().NamingStrategy)
+}
+
+// introspectionDB returns the database handle used for introspection. Its
+// queries are not logged, so that generated output can be piped.
+func (a *Accessor) introspectionDB() *gorm.DB {
+	return a.migrationDB().Session(&gorm.Session{Logger: logger.Discard})
+}
+
+// inspectTables introspects the named tables, or all of them when names
+// is empty.
+func (a *Accessor) inspectTables(names []string) ([]inspectedTable, error) {
+	m := a.introspectionDB().Migrator()
+
+	if len(names) == 0 {
+		all, err := m.GetTables()
+		if err != nil {
+			return nil, translateError(err)
+		}
+		for _, name := range all {
+			if strings.HasPrefix(name, "sqlite_") || name == MigrationTableName || name == migrationLockTable {
+				continue
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	tables := make([]inspectedTable, 0, len(names))
+	for _, name := range names {
+		if !m.HasTable(name) {
+			return nil, fmt.Errorf("table %q does not exist", name)
+		}
+
+		columns, err := a.columnTypes(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect columns of %s: %w", name, translateError(err))
+		}
+		indexes, err := m.GetIndexes(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect indexes of %s: %w", name, translateError(err))
+		}
+		if a.migrationDB().Dialector.Name() != dialectPostgres {
+			constraints, err := a.sqliteUniqueConstraints(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to inspect indexes of %s: %w", name, translateError(err))
+			}
+			indexes = append(indexes, constraints...)
+		}
+		sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name() < indexes[j].Name() })
+		foreignKeys, err := a.foreignKeys(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect foreign keys of %s: %w", name, translateError(err))
+		}
+
+		tables = append(tables, inspectedTable{Name: name, Columns: columns, Indexes: indexes, ForeignKeys: foreignKeys})
+	}
+	return tables, nil
+}
+
+// columnTypes returns the columns of a table.
+func (a *Accessor) columnTypes(table string) ([]gorm.ColumnType, error) {
+	if a.migrationDB().Dialector.Name() == dialectPostgres {
+		return a.introspectionDB().Migrator().ColumnTypes(table)
+	}
+	return a.sqliteColumnTypes(table)
+}
+
+// sqliteColumnTypes returns the columns of a SQLite table. GORM's
+// ColumnTypes parses the CREATE TABLE statement, which fails on statements
+// spanning several lines, so the table_info pragma is used instead.
+func (a *Accessor) sqliteColumnTypes(table string) ([]gorm.ColumnType, error) {
+	var rows []struct {
+		Name         string
+		Type         string
+		NotNull      bool
+		DefaultValue sql.NullString
+		PK           int
+	}
+	db := a.introspectionDB()
+	err := db.Raw(`SELECT name, type, "notnull" AS not_null, dflt_value AS default_value, pk
+FROM pragma_table_info(?) ORDER BY cid`, table).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Single-column UNIQUE constraints
+	var unique []string
+	err = db.Raw(`SELECT ii.name FROM pragma_index_list(?) il, pragma_index_info(il.name) ii
+WHERE il.origin = 'u' AND (SELECT count(*) FROM pragma_index_info(il.name)) = 1`, table).Scan(&unique).Error
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]gorm.ColumnType, 0, len(rows))
+	for _, row := range rows {
+		typeName, length, scale := parseColumnType(row.Type)
+		defaultValue := row.DefaultValue
+		// SQLite accepts double-quoted string defaults, which gorm tags cannot hold
+		if value := defaultValue.String; len(value) > 1 && value[0] == '"' && value[len(value)-1] == '"' {
+			defaultValue.String = "'" + value[1:len(value)-1] + "'"
+		}
+		primaryKey := row.PK > 0
+		columns = append(columns, migrator.ColumnType{
+			NameValue:          sql.NullString{String: row.Name, Valid: true},
+			DataTypeValue:      sql.NullString{String: typeName, Valid: true},
+			ColumnTypeValue:    sql.NullString{String: row.Type, Valid: true},
+			PrimaryKeyValue:    sql.NullBool{Bool: primaryKey, Valid: true},
+			UniqueValue:        sql.NullBool{Bool: slices.Contains(unique, row.Name), Valid: true},
+			AutoIncrementValue: sql.NullBool{Bool: primaryKey && typeName == "integer", Valid: true},
+			LengthValue:        sql.NullInt64{Int64: length, Valid: true},
+			DecimalSizeValue:   sql.NullInt64{Int64: length, Valid: true},
+			ScaleValue:         sql.NullInt64{Int64: scale, Valid: true},
+			NullableValue:      sql.NullBool{Bool: !row.NotNull && !primaryKey, Valid: true},
+			ScanTypeValue:      reflect.TypeOf(""),
+			DefaultValueValue:  defaultValue,
+		})
+	}
+	return columns, nil
+}
+
+// parseColumnType splits a declared column type such as "varchar(80)" or
+// "decimal(10,2)" into its name and sizes, which are zero when absent.
+func parseColumnType(declared string) (name string, length, scale int64) {
+	name = strings.ToLower(strings.TrimSpace(declared))
+	open := strings.IndexByte(name, '(')
+	if open < 0 || !strings.HasSuffix(name, ")") {
+		return name, 0, 0
+	}
+
+	sizes := strings.Split(name[open+1:len(name)-1], ",")
+	name = strings.TrimSpace(name[:open])
+	length, err := strconv.ParseInt(strings.TrimSpace(sizes[0]), 10, 64)
+	if err != nil {
+		return name, 0, 0
+	}
+	if len(sizes) > 1 {
+		scale, _ = strconv.ParseInt(strings.TrimSpace(sizes[1]), 10, 64)
+	}
+	return name, length, scale
+}
+
+// sqliteUniqueConstraints returns the multi-column UNIQUE constraints of a
+// SQLite table as indexes. GORM's GetIndexes leaves out the automatic
+// indexes backing them, while single-column ones are reported by
+// ColumnTypes. They are named like GORM names indexes, since SQLite
+// reserves their automatic names.
+func (a *Accessor) sqliteUniqueConstraints(table string) ([]gorm.Index, error) {
+	var names []string
+	db := a.introspectionDB()
+	if err := db.Raw(`SELECT name FROM pragma_index_list(?) WHERE origin = 'u'`, table).Scan(&names).Error; err != nil {
+		return nil, err
+	}
+
+	var indexes []gorm.Index
+	for _, name := range names {
+		var columns []string
+		if err := db.Raw(`SELECT name FROM pragma_index_info(?) ORDER BY seqno`, name).Scan(&columns).Error; err != nil {
+			return nil, err
+		}
+		if len(columns) < 2 {
+			continue
+		}
+		indexes = append(indexes, &migrator.Index{
+			TableName:       table,
+			NameValue:       "idx_" + table + "_" + strings.Join(columns, "_"),
+			ColumnList:      columns,
+			PrimaryKeyValue: sql.NullBool{Bool: false, Valid: true},
+			UniqueValue:     sql.NullBool{Bool: true, Valid: true},
+		})
+	}
+	return indexes, nil
+}
+
+// foreignKeys returns the single-column foreign keys of a table. Composite
+// foreign keys cannot be expressed as gorm associations and are skipped.
+func (a *Accessor) foreignKeys(table string) ([]foreignKey, error) {
+	var rows []foreignKey
+	db := a.introspectionDB()
+	if a.migrationDB().Dialector.Name() == dialectPostgres {
+		err := db.Raw(`SELECT tc.constraint_name AS "constraint", kcu.column_name AS "column",
+	ccu.table_name AS ref_table, ccu.column_name AS ref_column, rc.delete_rule AS on_delete
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu
+	ON kcu.constraint_name = tc.constraint_name AND kcu.table_schema = tc.table_schema
+JOIN information_schema.constraint_column_usage ccu
+	ON ccu.constraint_name = tc.constraint_name AND ccu.table_schema = tc.table_schema
+JOIN information_schema.referential_constraints rc
+	ON rc.constraint_name = tc.constraint_name AND rc.constraint_schema = tc.table_schema
+WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = CURRENT_SCHEMA() AND tc.table_name = ?
+ORDER BY kcu.ordinal_position`, table).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err := db.Raw(`SELECT CAST(id AS text) AS "constraint", "from" AS "column", "table" AS ref_table,
+	COALESCE("to", '') AS ref_column, on_delete
+FROM pragma_foreign_key_list(?) ORDER BY id, seq`, table).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	columns := map[string]int{}
+	for _, row := range rows {
+		columns[row.Constraint]++
+	}
+	var foreignKeys []foreignKey
+	for _, row := range rows {
+		if columns[row.Constraint] == 1 {
+			foreignKeys = append(foreignKeys, row)
+		}
+	}
+	return foreignKeys, nil
+}
+
+// inspectedField is a field of a generated model.
+type inspectedField struct {
+	Name    string
+	Type    string
+	Gorm    []string
+	JSON    string
+	Comment string
+}
+
+// inspectedModel is a generated model.
+type inspectedModel struct {
+	Name      string
+	Table     inspectedTable
+	BaseModel bool
+	Fields    []inspectedField
+}
+
+// inspectDBSource renders the models of the inspected tables as Go source.
+func inspectDBSource(tables []inspectedTable, pkg, dialect string, naming schema.Namer) ([]byte, error) {
+	models := make([]*inspectedModel, 0, len(tables))
+	byTable := map[string]*inspectedModel{}
+	names := map[string]bool{}
+	for _, table := range tables {
+		name := goIdentifier(inflection.Singular(table.Name))
+		if names[name] {
+			name = goIdentifier(table.Name)
+		}
+		for names[name] {
+			name += "Model"
+		}
+		names[name] = true
+
+		model := &inspectedModel{Name: name, Table: table, BaseModel: hasColumns(table, baseModelColumns...)}
+		models = append(models, model)
+		byTable[table.Name] = model
+	}
+
+	imports := map[string]bool{}
+	for _, model := range models {
+		model.Fields = inspectFields(model, byTable, dialect, naming)
+		if model.BaseModel {
+			imports["github.com/AIGamer28100/gobase"] = true
+		}
+		for _, field := range model.Fields {
+			switch strings.TrimPrefix(field.Type, "*") {
+			case "time.Time":
+				imports["time"] = true
+			case "gorm.DeletedAt":
+				imports["gorm.io/gorm"] = true
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Models generated by gobase inspectdb from a %s database. Review them\n", dialect)
+	buf.WriteString("// before use: fields of guessed types and associations may need adjusting.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Slice(paths, func(i, j int) bool {
+			if iStd, jStd := !strings.Contains(paths[i], "."), !strings.Contains(paths[j], "."); iStd != jStd {
+				return iStd
+			}
+			return paths[i] < paths[j]
+		})
+		buf.WriteString("import (\n")
+		for i, path := range paths {
+			// Standard library packages first, like goimports
+			if i > 0 && !strings.Contains(paths[i-1], ".") && strings.Contains(path, ".") {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(&buf, "%q\n", path)
+		}
+		buf.WriteString(")\n")
+	}
+
+	for _, model := range models {
+		fmt.Fprintf(&buf, "\n// %s is the model of the %s table\n", model.Name, model.Table.Name)
+		if !model.BaseModel {
+			buf.WriteString("//\n// It cannot embed gobase.BaseModel, as the table lacks some of the id,\n")
+			buf.WriteString("// created_at, updated_at and deleted_at columns; add them with a migration\n")
+			buf.WriteString("// to use the model with an Accessor.\n")
+		}
+		fmt.Fprintf(&buf, "type %s struct {\n", model.Name)
+		if model.BaseModel {
+			buf.WriteString("gobase.BaseModel\n")
+		}
+		for _, field := range model.Fields {
+			fmt.Fprintf(&buf, "%s %s `", field.Name, field.Type)
+			if len(field.Gorm) > 0 {
+				fmt.Fprintf(&buf, "gorm:%q ", strings.Join(field.Gorm, ";"))
+			}
+			fmt.Fprintf(&buf, "json:%q`", field.JSON)
+			if field.Comment != "" {
+				fmt.Fprintf(&buf, " // %s", field.Comment)
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n\n")
+		fmt.Fprintf(&buf, "// TableName specifies the table name for the %s model\n", model.Name)
+		fmt.Fprintf(&buf, "func (%s) TableName() string {\nreturn %q\n}\n", model.Name, model.Table.Name)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format models: %w", err)
+	}
+	return source, nil
+}
+
+// inspectFields returns the fields of a generated model: one per column,
+// except those provided by an embedded BaseModel, then one per association.
+func inspectFields(model *inspectedModel, byTable map[string]*inspectedModel, dialect string, naming schema.Namer) []inspectedField {
+	table := model.Table
+
+	// Indexes are declared on the fields of their columns
+	indexTags := map[string][]string{}
+	uniqueIndexed := map[string]bool{}
+	for _, index := range table.Indexes {
+		if primary, _ := index.PrimaryKey(); primary {
+			continue
+		}
+		tag := "index"
+		if unique, _ := index.Unique(); unique {
+			tag = "uniqueIndex"
+		}
+		columns := index.Columns()
+		if len(columns) == 1 {
+			if tag == "uniqueIndex" {
+				uniqueIndexed[columns[0]] = true
+			}
+			if index.Name() == "idx_"+table.Name+"_"+columns[0] {
+				indexTags[columns[0]] = append(indexTags[columns[0]], tag)
+				continue
+			}
+		}
+		for _, column := range columns {
+			indexTags[column] = append(indexTags[column], tag+":"+index.Name())
+		}
+	}
+
+	references := map[string]foreignKey{}
+	for _, fk := range table.ForeignKeys {
+		references[fk.Column] = fk
+	}
+
+	var fields []inspectedField
+	used := map[string]bool{}
+	fieldName := func(column string) string {
+		name := goIdentifier(column)
+		for used[name] {
+			name += "_"
+		}
+		used[name] = true
+		return name
+	}
+
+	for _, column := range table.Columns {
+		goType, guessed := columnGoType(column, dialect)
+		nullable, _ := column.Nullable()
+		primaryKey, _ := column.PrimaryKey()
+
+		if model.BaseModel && baseModelCompatible(column, goType) {
+			used[goIdentifier(column.Name())] = true
+			continue
+		}
+
+		field := inspectedField{Name: fieldName(column.Name()), JSON: column.Name()}
+		if naming.ColumnName("", field.Name) != column.Name() {
+			field.Gorm = append(field.Gorm, "column:"+column.Name())
+		}
+		if primaryKey {
+			field.Gorm = append(field.Gorm, "primaryKey")
+			nullable = false
+		} else if !nullable {
+			field.Gorm = append(field.Gorm, "not null")
+		}
+		if length, ok := column.Length(); ok && length > 0 && goType == "string" {
+			field.Gorm = append(field.Gorm, "size:"+strconv.FormatInt(length, 10))
+		}
+		if value, ok := columnDefault(column); ok {
+			field.Gorm = append(field.Gorm, "default:"+value)
+		}
+		if unique, _ := column.Unique(); unique && !primaryKey && !uniqueIndexed[column.Name()] {
+			field.Gorm = append(field.Gorm, "unique")
+		}
+		field.Gorm = append(field.Gorm, indexTags[column.Name()]...)
+
+		if fk, ok := references[column.Name()]; ok {
+			if target := byTable[fk.RefTable]; target != nil && target.BaseModel && (fk.RefColumn == "" || fk.RefColumn == "id") && isIntegerType(goType) {
+				goType = "uint"
+			}
+			ref := fk.RefColumn
+			if ref == "" {
+				ref = "its primary key"
+			}
+			field.Comment = fmt.Sprintf("references %s (%s)", fk.RefTable, ref)
+		}
+
+		switch {
+		case column.Name() == "deleted_at" && goType == "time.Time" && nullable:
+			goType = "gorm.DeletedAt"
+		case nullable && goType != "[]byte":
+			goType = "*" + goType
+		}
+		field.Type = goType
+		if guessed {
+			field.Comment = "Field type is a guess."
+		}
+		fields = append(fields, field)
+	}
+
+	// Foreign keys to inspected tables become belongs-to associations
+	for _, fk := range table.ForeignKeys {
+		target := byTable[fk.RefTable]
+		if target == nil || !strings.HasSuffix(fk.Column, "_id") {
+			continue
+		}
+		name := goIdentifier(strings.TrimSuffix(fk.Column, "_id"))
+		if used[name] {
+			continue
+		}
+		used[name] = true
+
+		tags := []string{"foreignKey:" + fieldNameOf(fields, fk.Column)}
+		if fk.RefColumn != "" && !(fk.RefColumn == "id" && (target.BaseModel || hasPrimaryKey(target.Table, "id"))) {
+			tags = append(tags, "references:"+goIdentifier(fk.RefColumn))
+		}
+		switch action := strings.ToUpper(fk.OnDelete); action {
+		case "CASCADE", "SET NULL", "SET DEFAULT", "RESTRICT":
+			tags = append(tags, "constraint:OnDelete:"+action)
+		}
+		fields = append(fields, inspectedField{
+			Name: name,
+			Type: "*" + target.Name,
+			Gorm: tags,
+			JSON: strings.TrimSuffix(fk.Column, "_id") + ",omitempty",
+		})
+	}
+	return fields
+}
+
+// fieldNameOf returns the name of the field generated for column.
+func fieldNameOf(fields []inspectedField, column string) string {
+	for _, field := range fields {
+		if field.JSON == column {
+			return field.Name
+		}
+	}
+	return goIdentifier(column)
+}
+
+// hasColumns reports whether the table has all the named columns.
+func hasColumns(table inspectedTable, names ...string) bool {
+	for _, name := range names {
+		found := false
+		for _, column := range table.Columns {
+			if column.Name() == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPrimaryKey reports whether column is the single primary key of table.
+func hasPrimaryKey(table inspectedTable, column string) bool {
+	var keys []string
+	for _, c := range table.Columns {
+		if primaryKey, _ := c.PrimaryKey(); primaryKey {
+			keys = append(keys, c.Name())
+		}
+	}
+	return len(keys) == 1 && keys[0] == column
+}
+
+// baseModelCompatible reports whether a column matches the BaseModel field
+// of the same name, so that the embedded field can be used as is.
+func baseModelCompatible(column gorm.ColumnType, goType string) bool {
+	nullable, _ := column.Nullable()
+	switch column.Name() {
+	case "id":
+		primaryKey, _ := column.PrimaryKey()
+		return primaryKey && isIntegerType(goType)
+	case "created_at", "updated_at":
+		return goType == "time.Time"
+	case "deleted_at":
+		return goType == "time.Time" && nullable
+	}
+	return false
+}
+
+// columnGoType returns the Go type of a column's values, and whether it is
+// a guess.
+func columnGoType(column gorm.ColumnType, dialect string) (string, bool) {
+	typeName := strings.ToLower(strings.TrimSpace(column.DatabaseTypeName()))
+	if i := strings.IndexByte(typeName, '('); i >= 0 {
+		typeName = strings.TrimSpace(typeName[:i])
+	}
+	typeName = strings.TrimSpace(strings.TrimSuffix(typeName, "unsigned"))
+
+	switch typeName {
+	case "integer", "int", "int4", "mediumint", "serial":
+		if dialect == dialectPostgres {
+			return "int32", false
+		}
+		return "int64", false
+	case "bigint", "int8", "bigserial":
+		return "int64", false
+	case "smallint", "int2", "smallserial":
+		return "int16", false
+	case "tinyint":
+		return "int8", false
+	case "bool", "boolean":
+		return "bool", false
+	case "real", "double", "double precision", "float", "float8":
+		return "float64", false
+	case "float4":
+		return "float32", false
+	case "numeric", "decimal":
+		// GORM stores booleans as numeric on SQLite
+		if value, ok := column.DefaultValue(); ok && (value == "true" || value == "false") {
+			return "bool", false
+		}
+		return "float64", true
+	case "text", "varchar", "character varying", "char", "character", "bpchar", "nvarchar",
+		"nchar", "clob", "citext", "uuid", "json", "jsonb", "string":
+		return "string", false
+	case "blob", "bytea":
+		return "[]byte", false
+	case "datetime", "timestamp", "timestamptz", "timestamp with time zone",
+		"timestamp without time zone", "date", "time", "timetz":
+		return "time.Time", false
+	}
+	return "string", true
+}
+
+// isIntegerType reports whether goType is an integer type.
+func isIntegerType(goType string) bool {
+	switch goType {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+// columnDefault returns the default of a column as a gorm default tag
+// value. Sequences and defaults gorm tags cannot hold are left out.
+func columnDefault(column gorm.ColumnType) (string, bool) {
+	value, ok := column.DefaultValue()
+	if !ok || value == "" {
+		return "", false
+	}
+	if autoIncrement, _ := column.AutoIncrement(); autoIncrement {
+		return "", false
+	}
+	if strings.HasPrefix(value, "nextval(") || strings.ContainsAny(value, ";\"`") {
+		return "", false
+	}
+	// Drop PostgreSQL casts such as 'user'::character varying
+	if i := strings.LastIndex(value, "::"); i > 0 && !strings.Contains(value[i:], "'") {
+		value = value[:i]
+	}
+	return value, true
+}
+
+// goIdentifier converts a snake_case database name to an exported Go
+// identifier, e.g. "user_id" to "UserID".
+func goIdentifier(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if upper := strings.ToUpper(word); goInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	identifier := b.String()
+	if identifier == "" || !unicode.IsLetter([]rune(identifier)[0]) {
+		identifier = "X" + identifier
+	}
+	return identifier
+}
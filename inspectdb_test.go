@@ -0,0 +1,141 @@
+package gobase
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// setupLegacyDB creates tables the way a legacy application would, next to gobase models
+func setupLegacyDB(t *testing.T) *Accessor {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&User{}, &Publisher{}, &Book{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	statements := []string{
+		`CREATE TABLE legacy_orders (
+			order_no integer PRIMARY KEY,
+			customer varchar(80) NOT NULL,
+			total numeric,
+			gift numeric DEFAULT false,
+			placed_at timestamp,
+			status text DEFAULT 'new',
+			api_key text UNIQUE,
+			user_id integer REFERENCES users(id) ON DELETE CASCADE,
+			UNIQUE (customer, placed_at)
+		)`,
+		`CREATE INDEX legacy_orders_status ON legacy_orders (status)`,
+		`CREATE TABLE sessions (
+			id text PRIMARY KEY,
+			created_at integer NOT NULL,
+			updated_at datetime,
+			deleted_at datetime
+		)`,
+	}
+	for _, statement := range statements {
+		if err := accessor.connection.GormDB.Exec(statement).Error; err != nil {
+			t.Fatalf("Failed to create legacy table: %v", err)
+		}
+	}
+	return accessor
+}
+
+// TestAccessor_InspectDB tests generating models from an existing database
+func TestAccessor_InspectDB(t *testing.T) {
+	accessor := setupLegacyDB(t)
+
+	source, err := accessor.InspectDB(InspectDBOptions{})
+	if err != nil {
+		t.Fatalf("InspectDB failed: %v", err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "models.go", source, 0)
+	if err != nil {
+		t.Fatalf("Expected valid Go source: %v\n%s", err, source)
+	}
+	if file.Name.Name != "models" {
+		t.Errorf("Expected package models, got %s", file.Name.Name)
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "Model per table", expected: "type LegacyOrder struct {"},
+		{name: "Table name", expected: `return "legacy_orders"`},
+		{name: "Embedded BaseModel", expected: "type Book struct {\n\tgobase.BaseModel\n"},
+		{name: "Primary key", expected: "OrderNo  int64      `gorm:\"primaryKey\" json:\"order_no\"`"},
+		{name: "Not null and size", expected: "`gorm:\"not null;size:80;uniqueIndex:idx_legacy_orders_customer_placed_at\" json:\"customer\"`"},
+		{name: "Nullable", expected: "PlacedAt *time.Time"},
+		{name: "Default", expected: "`gorm:\"default:'new';index:legacy_orders_status\" json:\"status\"`"},
+		{name: "Unique column", expected: "APIKey   *string    `gorm:\"unique\" json:\"api_key\"`"},
+		{name: "Unique index", expected: "Username     string     `gorm:\"not null;uniqueIndex\" json:\"username\"`"},
+		{name: "Guessed type", expected: "// Field type is a guess."},
+		{name: "Numeric without boolean default", expected: "Total    *float64"},
+		{name: "Foreign key to BaseModel", expected: "UserID   *uint"},
+		{name: "Association", expected: "User     *User      `gorm:\"foreignKey:UserID;constraint:OnDelete:CASCADE\" json:\"user,omitempty\"`"},
+		{name: "Boolean stored as numeric", expected: "IsActive     *bool"},
+		{name: "Nullable boolean", expected: "Gift     *bool      `gorm:\"default:false\" json:\"gift\"`"},
+		{name: "Overridden BaseModel fields", expected: "type Session struct {\n\tgobase.BaseModel\n\tID        string `gorm:\"primaryKey\" json:\"id\"`\n\tCreatedAt int64  `gorm:\"not null\" json:\"created_at\"`\n}"},
+		{name: "Plain struct without BaseModel", expected: "// It cannot embed gobase.BaseModel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(string(source), tt.expected) {
+				t.Errorf("Expected source to contain %q, got:\n%s", tt.expected, source)
+			}
+		})
+	}
+
+	if strings.Contains(string(source), MigrationTableName) {
+		t.Error("Expected gobase tables to be skipped")
+	}
+}
+
+// TestAccessor_InspectDBTables tests inspecting selected tables
+func TestAccessor_InspectDBTables(t *testing.T) {
+	accessor := setupLegacyDB(t)
+
+	source, err := accessor.InspectDB(InspectDBOptions{Package: "legacy", Tables: []string{"legacy_orders"}})
+	if err != nil {
+		t.Fatalf("InspectDB failed: %v", err)
+	}
+	if !strings.Contains(string(source), "package legacy") {
+		t.Errorf("Expected package legacy, got:\n%s", source)
+	}
+	if strings.Contains(string(source), "type User struct") {
+		t.Errorf("Expected only legacy_orders, got:\n%s", source)
+	}
+	// Foreign keys to tables left out keep a comment instead of an association
+	if !strings.Contains(string(source), "UserID   *int64") || !strings.Contains(string(source), "// references users (id)") {
+		t.Errorf("Expected plain foreign key field, got:\n%s", source)
+	}
+
+	if _, err := accessor.InspectDB(InspectDBOptions{Tables: []string{"missing"}}); err == nil {
+		t.Error("Expected error for unknown table")
+	}
+}
+
+// TestGoIdentifier tests converting database names to Go identifiers
+func TestGoIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "user_id", expected: "UserID"},
+		{name: "api_key", expected: "APIKey"},
+		{name: "firstName", expected: "FirstName"},
+		{name: "order-lines", expected: "OrderLines"},
+		{name: "2fa_secret", expected: "X2faSecret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := goIdentifier(tt.name); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
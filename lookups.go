@@ -0,0 +1,325 @@
+package gobase
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// lookupSeparator separates a field name from its lookup, e.g. "views__gte".
+const lookupSeparator = "__"
+
+// Supported Django-style field lookups.
+const (
+	LookupExact       = "exact"
+	LookupIExact      = "iexact"
+	LookupContains    = "contains"
+	LookupIContains   = "icontains"
+	LookupStartsWith  = "startswith"
+	LookupIStartsWith = "istartswith"
+	LookupEndsWith    = "endswith"
+	LookupIEndsWith   = "iendswith"
+	LookupGt          = "gt"
+	LookupGte         = "gte"
+	LookupLt          = "lt"
+	LookupLte         = "lte"
+	LookupIn          = "in"
+	LookupRange       = "range"
+	LookupIsNull      = "isnull"
+)
+
+// Supported Django-style transforms. A transform may be used on its own
+// ("created_at__year") or followed by a comparison ("created_at__year__gte").
+const (
+	TransformDate  = "date"
+	TransformYear  = "year"
+	TransformMonth = "month"
+)
+
+// Database dialect names as reported by the GORM dialector.
+const (
+	dialectSQLite   = "sqlite"
+	dialectPostgres = "postgres"
+)
+
+// lookup is a parsed condition key such as "created_at__year__gte".
+type lookup struct {
+	field     string
+	transform string
+	operator  string
+}
+
+// parseLookup splits a condition key into field, optional transform and
+// lookup operator. Keys without a lookup default to "exact".
+func parseLookup(key string) (lookup, error) {
+	parts := strings.Split(key, lookupSeparator)
+	l := lookup{field: parts[0], operator: LookupExact}
+
+	if l.field == "" {
+		return l, fmt.Errorf("invalid lookup %q: missing field name", key)
+	}
+
+	rest := parts[1:]
+	if len(rest) > 0 && isTransform(rest[0]) {
+		l.transform = rest[0]
+		rest = rest[1:]
+	}
+
+	switch len(rest) {
+	case 0:
+	case 1:
+		if !isLookupOperator(rest[0]) {
+			return l, fmt.Errorf("unsupported lookup %q in %q", rest[0], key)
+		}
+		l.operator = rest[0]
+	default:
+		return l, fmt.Errorf("unsupported lookup %q in %q", strings.Join(rest, lookupSeparator), key)
+	}
+
+	if l.transform != "" && !isComparisonOperator(l.operator) {
+		return l, fmt.Errorf("lookup %q cannot be combined with %q in %q", l.operator, l.transform, key)
+	}
+
+	return l, nil
+}
+
+// isTransform reports whether name is a supported transform.
+func isTransform(name string) bool {
+	switch name {
+	case TransformDate, TransformYear, TransformMonth:
+		return true
+	}
+	return false
+}
+
+// isLookupOperator reports whether name is a supported lookup operator.
+func isLookupOperator(name string) bool {
+	switch name {
+	case LookupExact, LookupIExact, LookupContains, LookupIContains,
+		LookupStartsWith, LookupIStartsWith, LookupEndsWith, LookupIEndsWith,
+		LookupGt, LookupGte, LookupLt, LookupLte, LookupIn, LookupRange, LookupIsNull:
+		return true
+	}
+	return false
+}
+
+// isComparisonOperator reports whether the operator compares values and can
+// therefore be applied to a transformed field.
+func isComparisonOperator(name string) bool {
+	switch name {
+	case LookupExact, LookupGt, LookupGte, LookupLt, LookupLte, LookupIn, LookupRange:
+		return true
+	}
+	return false
+}
+
+// compileConditions turns a map of lookups into a single SQL expression
+// joined with AND, or nil when there are no conditions. Field names are
+// resolved against the model schema, and keys are sorted so the generated
+// SQL is stable.
+func compileConditions(dialect string, s *schema.Schema, conditions map[string]interface{}) (clause.Expression, error) {
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	exprs := make([]clause.Expression, 0, len(keys))
+	for _, key := range keys {
+		l, err := parseLookup(key)
+		if err != nil {
+			return nil, err
+		}
+
+		column, err := resolveField(s, l.field)
+		if err != nil {
+			return nil, err
+		}
+
+		expr, err := l.expr(dialect, clause.Column{Name: column}, conditions[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %w", key, err)
+		}
+		if l.nullable(s.LookUpField(l.field), conditions[key]) {
+			expr = nullableExpr{expr: expr, column: clause.Column{Name: column}}
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	return joinExprs(clause.AndWithSpace, exprs), nil
+}
+
+// nullable reports whether the lookup on field evaluates to NULL, rather
+// than false, for rows where the column is NULL.
+func (l lookup) nullable(field *schema.Field, value interface{}) bool {
+	if field.NotNull || field.PrimaryKey {
+		return false
+	}
+	return l.operator != LookupIsNull && !(l.operator == LookupExact && value == nil)
+}
+
+// expr builds the SQL expression for the lookup applied to column.
+func (l lookup) expr(dialect string, column clause.Column, value interface{}) (clause.Expression, error) {
+	lhs := transformExpr(dialect, l.transform, column)
+	if l.transform == TransformDate {
+		value = dateValue(value)
+	}
+
+	switch l.operator {
+	case LookupExact:
+		if value == nil {
+			return clause.Expr{SQL: "? IS NULL", Vars: []interface{}{lhs}}, nil
+		}
+		return clause.Expr{SQL: "? = ?", Vars: []interface{}{lhs, value}}, nil
+	case LookupGt:
+		return clause.Expr{SQL: "? > ?", Vars: []interface{}{lhs, value}}, nil
+	case LookupGte:
+		return clause.Expr{SQL: "? >= ?", Vars: []interface{}{lhs, value}}, nil
+	case LookupLt:
+		return clause.Expr{SQL: "? < ?", Vars: []interface{}{lhs, value}}, nil
+	case LookupLte:
+		return clause.Expr{SQL: "? <= ?", Vars: []interface{}{lhs, value}}, nil
+	case LookupIn:
+		values, err := sliceValues(value)
+		if err != nil {
+			return nil, err
+		}
+		return clause.Expr{SQL: "? IN ?", Vars: []interface{}{lhs, values}}, nil
+	case LookupRange:
+		values, err := rangeValues(value)
+		if err != nil {
+			return nil, err
+		}
+		return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []interface{}{lhs, values[0], values[1]}}, nil
+	case LookupIsNull:
+		isNull, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("isnull requires a bool, got %T", value)
+		}
+		if isNull {
+			return clause.Expr{SQL: "? IS NULL", Vars: []interface{}{lhs}}, nil
+		}
+		return clause.Expr{SQL: "? IS NOT NULL", Vars: []interface{}{lhs}}, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s requires a string, got %T", l.operator, value)
+	}
+	return patternExpr(dialect, l.operator, lhs, s), nil
+}
+
+// transformExpr wraps column in the SQL function implementing transform.
+func transformExpr(dialect, transform string, column clause.Column) interface{} {
+	switch transform {
+	case TransformDate:
+		if dialect == dialectSQLite {
+			return clause.Expr{SQL: "date(?)", Vars: []interface{}{column}}
+		}
+		return clause.Expr{SQL: "CAST(? AS DATE)", Vars: []interface{}{column}}
+	case TransformYear:
+		if dialect == dialectSQLite {
+			return clause.Expr{SQL: "CAST(strftime('%Y', ?) AS INTEGER)", Vars: []interface{}{column}}
+		}
+		return clause.Expr{SQL: "EXTRACT(YEAR FROM ?)", Vars: []interface{}{column}}
+	case TransformMonth:
+		if dialect == dialectSQLite {
+			return clause.Expr{SQL: "CAST(strftime('%m', ?) AS INTEGER)", Vars: []interface{}{column}}
+		}
+		return clause.Expr{SQL: "EXTRACT(MONTH FROM ?)", Vars: []interface{}{column}}
+	}
+	return column
+}
+
+// patternExpr builds the text matching lookups (iexact, contains,
+// startswith, endswith and their case-insensitive variants).
+//
+// SQLite's LIKE is case-insensitive, so case-sensitive matching uses GLOB
+// there, while PostgreSQL uses LIKE and ILIKE.
+func patternExpr(dialect, operator string, lhs interface{}, value string) clause.Expression {
+	insensitive := strings.HasPrefix(operator, "i")
+	prefix, suffix := "", ""
+	switch strings.TrimPrefix(operator, "i") {
+	case LookupContains:
+		prefix, suffix = "%", "%"
+	case LookupStartsWith:
+		suffix = "%"
+	case LookupEndsWith:
+		prefix = "%"
+	}
+
+	switch {
+	case dialect == dialectSQLite && operator == LookupIExact:
+		return clause.Expr{SQL: "? = ? COLLATE NOCASE", Vars: []interface{}{lhs, value}}
+	case dialect == dialectSQLite && !insensitive:
+		pattern := strings.ReplaceAll(prefix, "%", "*") + escapeGlob(value) + strings.ReplaceAll(suffix, "%", "*")
+		return clause.Expr{SQL: "? GLOB ?", Vars: []interface{}{lhs, pattern}}
+	case dialect == dialectSQLite:
+		return clause.Expr{SQL: `? LIKE ? ESCAPE '\'`, Vars: []interface{}{lhs, prefix + escapeLike(value) + suffix}}
+	case dialect == dialectPostgres && insensitive:
+		return clause.Expr{SQL: `? ILIKE ? ESCAPE '\'`, Vars: []interface{}{lhs, prefix + escapeLike(value) + suffix}}
+	case insensitive:
+		return clause.Expr{SQL: `LOWER(?) LIKE LOWER(?) ESCAPE '\'`, Vars: []interface{}{lhs, prefix + escapeLike(value) + suffix}}
+	default:
+		return clause.Expr{SQL: `? LIKE ? ESCAPE '\'`, Vars: []interface{}{lhs, prefix + escapeLike(value) + suffix}}
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in s using backslash.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// escapeGlob escapes the GLOB wildcards in s by wrapping them in brackets.
+func escapeGlob(s string) string {
+	return strings.NewReplacer(`*`, `[*]`, `?`, `[?]`, `[`, `[[]`).Replace(s)
+}
+
+// dateValue formats time values as dates for the date transform.
+func dateValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format("2006-01-02")
+	case []time.Time:
+		dates := make([]interface{}, len(v))
+		for i, t := range v {
+			dates[i] = t.UTC().Format("2006-01-02")
+		}
+		return dates
+	}
+	return value
+}
+
+// sliceValues converts a slice or array value into []interface{}.
+func sliceValues(value interface{}) ([]interface{}, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a slice, got %T", value)
+	}
+
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, nil
+}
+
+// rangeValues returns the bounds of a range lookup.
+func rangeValues(value interface{}) ([]interface{}, error) {
+	values, err := sliceValues(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 2 {
+		return nil, fmt.Errorf("range requires exactly 2 values, got %d", len(values))
+	}
+	return values, nil
+}
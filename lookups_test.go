@@ -0,0 +1,128 @@
+package gobase
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseLookup tests splitting condition keys into field and lookup
+func TestParseLookup(t *testing.T) {
+	tests := []struct {
+		key         string
+		expected    lookup
+		expectError bool
+	}{
+		{key: "name", expected: lookup{field: "name", operator: LookupExact}},
+		{key: "views__gte", expected: lookup{field: "views", operator: LookupGte}},
+		{key: "title__icontains", expected: lookup{field: "title", operator: LookupIContains}},
+		{key: "created_at__year", expected: lookup{field: "created_at", transform: TransformYear, operator: LookupExact}},
+		{key: "created_at__month__in", expected: lookup{field: "created_at", transform: TransformMonth, operator: LookupIn}},
+		{key: "views__between", expectError: true},
+		{key: "created_at__year__icontains", expectError: true},
+		{key: "views__gt__lt", expectError: true},
+		{key: "__gt", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			l, err := parseLookup(tt.key)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if l != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, l)
+			}
+		})
+	}
+}
+
+// TestAccessor_FilterLookups tests field lookups against SQLite
+func TestAccessor_FilterLookups(t *testing.T) {
+	accessor := setupArticles(t)
+
+	old := &Article{Title: "100% Go_Lang", Author: "Dave", Status: "archived", Views: 5}
+	old.CreatedAt = time.Date(2020, time.March, 15, 12, 0, 0, 0, time.UTC)
+	if err := accessor.Create(old); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   int
+	}{
+		{name: "exact", conditions: map[string]interface{}{"author__exact": "alice"}, expected: 2},
+		{name: "iexact", conditions: map[string]interface{}{"author__iexact": "DAVE"}, expected: 1},
+		{name: "contains is case-sensitive", conditions: map[string]interface{}{"title__contains": "GO"}, expected: 0},
+		{name: "contains", conditions: map[string]interface{}{"title__contains": "Go"}, expected: 3},
+		{name: "icontains", conditions: map[string]interface{}{"title__icontains": "go"}, expected: 4},
+		{name: "contains escapes wildcards", conditions: map[string]interface{}{"title__contains": "0% Go_"}, expected: 1},
+		{name: "icontains escapes wildcards", conditions: map[string]interface{}{"title__icontains": "%"}, expected: 1},
+		{name: "startswith", conditions: map[string]interface{}{"title__startswith": "Go"}, expected: 1},
+		{name: "istartswith", conditions: map[string]interface{}{"title__istartswith": "d"}, expected: 2},
+		{name: "endswith", conditions: map[string]interface{}{"title__endswith": "Go"}, expected: 1},
+		{name: "iendswith", conditions: map[string]interface{}{"title__iendswith": "LANG"}, expected: 1},
+		{name: "gt", conditions: map[string]interface{}{"views__gt": 10}, expected: 2},
+		{name: "gte", conditions: map[string]interface{}{"views__gte": 10}, expected: 3},
+		{name: "lt", conditions: map[string]interface{}{"views__lt": 10}, expected: 2},
+		{name: "lte", conditions: map[string]interface{}{"views__lte": 10}, expected: 3},
+		{name: "in", conditions: map[string]interface{}{"author__in": []string{"bob", "carol"}}, expected: 2},
+		{name: "empty in", conditions: map[string]interface{}{"author__in": []string{}}, expected: 0},
+		{name: "range", conditions: map[string]interface{}{"views__range": []int{5, 30}}, expected: 3},
+		{name: "isnull", conditions: map[string]interface{}{"deleted_at__isnull": true}, expected: 5},
+		{name: "exact nil", conditions: map[string]interface{}{"deleted_at": nil}, expected: 5},
+		{name: "year", conditions: map[string]interface{}{"created_at__year": 2020}, expected: 1},
+		{name: "year gte", conditions: map[string]interface{}{"created_at__year__gte": 2021}, expected: 4},
+		{name: "month", conditions: map[string]interface{}{"created_at__month": 3, "created_at__year": 2020}, expected: 1},
+		{name: "date", conditions: map[string]interface{}{"created_at__date": old.CreatedAt}, expected: 1},
+		{name: "date string", conditions: map[string]interface{}{"created_at__date": "2020-03-15"}, expected: 1},
+		{name: "combined", conditions: map[string]interface{}{"status": "published", "views__gt": 20}, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			err := accessor.Filter(&articles, tt.conditions)
+			if err != nil {
+				t.Fatalf("Filter failed: %v", err)
+			}
+
+			if len(articles) != tt.expected {
+				t.Errorf("Expected %d articles, got %d", tt.expected, len(articles))
+			}
+		})
+	}
+}
+
+// TestAccessor_FilterLookupErrors tests invalid lookups and values
+func TestAccessor_FilterLookupErrors(t *testing.T) {
+	accessor := setupArticles(t)
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+	}{
+		{name: "unknown lookup", conditions: map[string]interface{}{"views__between": 1}},
+		{name: "in without slice", conditions: map[string]interface{}{"views__in": 1}},
+		{name: "range with wrong length", conditions: map[string]interface{}{"views__range": []int{1}}},
+		{name: "isnull without bool", conditions: map[string]interface{}{"deleted_at__isnull": "yes"}},
+		{name: "contains without string", conditions: map[string]interface{}{"title__contains": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			if err := accessor.Filter(&articles, tt.conditions); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
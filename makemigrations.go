@@ -0,0 +1,522 @@
+package gobase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// MigrationStateFile is the file in a migrations directory recording the
+// schema of the models as of the latest generated migration. MakeMigrations
+// diffs the models against it, so it must be committed along with the
+// migrations.
+const MigrationStateFile = "gobase_state.json"
+
+// Migration file formats written by MakeMigrations.
+const (
+	MigrationFormatSQL = "sql"
+	MigrationFormatGo  = "go"
+)
+
+// migrationFile matches the files of a migrations directory.
+var migrationFile = regexp.MustCompile(`^(\d+)_[A-Za-z0-9_]+\.(sql|go)$`)
+
+// migrationLabel restricts migration names to what migrationName accepts.
+var migrationLabel = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// MakeMigrationsOptions configures MakeMigrations.
+type MakeMigrationsOptions struct {
+	// Name is the descriptive part of the migration name, e.g.
+	// "add_article_views". It defaults to "initial" for the first
+	// migration and "auto_<timestamp>" afterwards.
+	Name string
+	// Format is MigrationFormatSQL (the default) or MigrationFormatGo.
+	Format string
+	// Package is the package clause of Go migration files. It defaults to
+	// the name of the migrations directory, or "migrations" if that is not
+	// a valid package name.
+	Package string
+}
+
+// schemaState is the recorded schema of the migrated models.
+type schemaState struct {
+	Dialect string       `json:"dialect"`
+	Tables  []tableState `json:"tables"` // in creation order
+}
+
+// tableState is the recorded schema of a table.
+type tableState struct {
+	Name    string        `json:"name"`
+	Create  []string      `json:"create"` // statements creating the table without its indexes
+	Columns []columnState `json:"columns"`
+	Indexes []indexState  `json:"indexes"`
+}
+
+// columnState is the recorded schema of a column.
+type columnState struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	NotNull    bool   `json:"not_null,omitempty"`
+	Default    string `json:"default,omitempty"`
+	Definition string `json:"definition"` // type with constraints, as used by ADD COLUMN
+}
+
+// indexState is the recorded schema of an index.
+type indexState struct {
+	Name   string `json:"name"`
+	Create string `json:"create"`
+}
+
+// table returns the recorded table with the given name, or nil.
+func (s *schemaState) table(name string) *tableState {
+	for i := range s.Tables {
+		if s.Tables[i].Name == name {
+			return &s.Tables[i]
+		}
+	}
+	return nil
+}
+
+// column returns the recorded column with the given name, or nil.
+func (t *tableState) column(name string) *columnState {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// index returns the recorded index with the given name, or nil.
+func (t *tableState) index(name string) *indexState {
+	for i := range t.Indexes {
+		if t.Indexes[i].Name == name {
+			return &t.Indexes[i]
+		}
+	}
+	return nil
+}
+
+// MakeMigrations writes a migration to dir that brings the schema recorded
+// in dir's MigrationStateFile up to date with the models, like Django's
+// makemigrations, and updates the state file. It diffs the given models,
+// or the registered models when none are given, and returns the path of
+// the new migration file, or "" when nothing changed.
+//
+// Migrations contain the SQL of the connected database's dialect. Tables,
+// columns and indexes are created and dropped; changing a column's type,
+// nullability or default is only supported on PostgreSQL. Renames are seen
+// as a drop and an add, so review generated migrations before applying
+// them, as they may lose data.
+//
+//	path, err := accessor.MakeMigrations("migrations", gobase.MakeMigrationsOptions{Name: "add_article_views"})
+func (a *Accessor) MakeMigrations(dir string, options MakeMigrationsOptions, models ...interface{}) (string, error) {
+	// Only supported by Migrator backends
+	if err := a.requireMigrator("MakeMigrations"); err != nil {
+		return "", err
+	}
+
+	if options.Format == "" {
+		options.Format = MigrationFormatSQL
+	}
+	if options.Format != MigrationFormatSQL && options.Format != MigrationFormatGo {
+		return "", fmt.Errorf("unsupported migration format %q", options.Format)
+	}
+	if options.Name != "" && !migrationLabel.MatchString(options.Name) {
+		return "", fmt.Errorf("invalid migration name %q: use letters, digits and underscores", options.Name)
+	}
+
+	models = migrationModels(models)
+	if len(models) == 0 {
+		return "", errors.New("no models to migrate")
+	}
+
+	previous, err := readSchemaState(filepath.Join(dir, MigrationStateFile))
+	if err != nil {
+		return "", err
+	}
+	dialect := a.migrationDB().Dialector.Name()
+	if previous.Dialect == "" {
+		previous.Dialect = dialect
+	}
+	if previous.Dialect != dialect {
+		return "", fmt.Errorf("migrations in %s were generated for %s, not %s", dir, previous.Dialect, dialect)
+	}
+
+	current, err := a.schemaState(models)
+	if err != nil {
+		return "", err
+	}
+
+	up, down, err := a.diffSchemaStates(previous, current)
+	if err != nil {
+		return "", err
+	}
+	if len(up) == 0 {
+		return "", nil
+	}
+
+	number, err := nextMigrationNumber(dir)
+	if err != nil {
+		return "", err
+	}
+	label := options.Name
+	if label == "" {
+		label = "initial"
+		if number > 1 {
+			label = "auto_" + a.migrationDB().NowFunc().Format("20060102_1504")
+		}
+	}
+	migration := Migration{Name: fmt.Sprintf("%04d_%s", number, label), Up: up, Down: down}
+
+	var content []byte
+	if options.Format == MigrationFormatGo {
+		pkg := options.Package
+		if pkg == "" {
+			pkg = "migrations"
+			if abs, err := filepath.Abs(dir); err == nil && token.IsIdentifier(strings.ToLower(filepath.Base(abs))) {
+				pkg = strings.ToLower(filepath.Base(abs))
+			}
+		}
+		content, err = goMigrationSource(migration, pkg)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		content = sqlMigrationSource(migration)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, migration.Name+"."+options.Format)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", err
+	}
+	if err := writeSchemaState(filepath.Join(dir, MigrationStateFile), current); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// sqlRecorder is a GORM logger collecting the SQL of every statement, used
+// with dry-run sessions to capture the DDL generated by GORM's migrator.
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+// LogMode implements logger.Interface.
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+// Trace implements logger.Interface.
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// recordDDL returns the statements fn would execute through the migrator,
+// without executing them.
+func (a *Accessor) recordDDL(fn func(gorm.Migrator) error) ([]string, error) {
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db := a.migrationDB().Session(&gorm.Session{DryRun: true, Logger: recorder})
+	if err := fn(db.Migrator()); err != nil {
+		return nil, err
+	}
+	return recorder.statements, nil
+}
+
+// schemaState returns the schema of models as GORM would create it.
+func (a *Accessor) schemaState(models []interface{}) (*schemaState, error) {
+	state := &schemaState{Dialect: a.migrationDB().Dialector.Name()}
+
+	// Order models so that referenced tables are created first
+	if reorderer, ok := a.migrationDB().Migrator().(interface {
+		ReorderModels([]interface{}, bool) []interface{}
+	}); ok {
+		models = reorderer.ReorderModels(models, false)
+	}
+
+	for _, model := range models {
+		s, err := a.modelSchema(model)
+		if err != nil {
+			return nil, err
+		}
+		if state.table(s.Table) != nil {
+			continue
+		}
+		table := tableState{Name: s.Table}
+
+		indexSQL := map[string]bool{}
+		for _, idx := range s.ParseIndexes() {
+			statements, err := a.recordDDL(func(m gorm.Migrator) error { return m.CreateIndex(model, idx.Name) })
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate index %s: %w", idx.Name, err)
+			}
+			if len(statements) != 1 {
+				return nil, fmt.Errorf("failed to generate index %s: expected a single statement, got %d", idx.Name, len(statements))
+			}
+			indexSQL[statements[0]] = true
+			table.Indexes = append(table.Indexes, indexState{Name: idx.Name, Create: statements[0]})
+		}
+		sort.Slice(table.Indexes, func(i, j int) bool { return table.Indexes[i].Name < table.Indexes[j].Name })
+
+		statements, err := a.recordDDL(func(m gorm.Migrator) error { return m.CreateTable(model) })
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate table %s: %w", s.Table, err)
+		}
+		for _, statement := range statements {
+			if !indexSQL[statement] {
+				table.Create = append(table.Create, statement)
+			}
+		}
+
+		migrator := a.migrationDB().Migrator()
+		for _, dbName := range s.DBNames {
+			field := s.FieldsByDBName[dbName]
+			if field.IgnoreMigration {
+				continue
+			}
+			table.Columns = append(table.Columns, columnState{
+				Name:       dbName,
+				Type:       a.migrationDB().Dialector.DataTypeOf(field),
+				NotNull:    field.NotNull,
+				Default:    field.DefaultValue,
+				Definition: migrator.FullDataTypeOf(field).SQL,
+			})
+		}
+
+		state.Tables = append(state.Tables, table)
+	}
+
+	return state, nil
+}
+
+// schemaChange is a reversible step of a generated migration.
+type schemaChange struct {
+	up, down []string
+}
+
+// diffSchemaStates returns the statements migrating the schema from
+// previous to current and back.
+func (a *Accessor) diffSchemaStates(previous, current *schemaState) ([]string, []string, error) {
+	quote := a.migrationDB().Statement.Quote
+	postgres := current.Dialect == dialectPostgres
+	var changes []schemaChange
+
+	for _, table := range current.Tables {
+		old := previous.table(table.Name)
+		if old == nil {
+			change := schemaChange{up: append([]string(nil), table.Create...), down: []string{"DROP TABLE " + quote(table.Name)}}
+			for _, idx := range table.Indexes {
+				change.up = append(change.up, idx.Create)
+			}
+			changes = append(changes, change)
+			continue
+		}
+
+		alter := "ALTER TABLE " + quote(table.Name) + " "
+
+		// Drop changed and removed indexes first, as they may cover
+		// columns about to be dropped
+		for _, idx := range old.Indexes {
+			if current := table.index(idx.Name); current == nil || current.Create != idx.Create {
+				changes = append(changes, schemaChange{up: []string{"DROP INDEX " + quote(idx.Name)}, down: []string{idx.Create}})
+			}
+		}
+
+		for _, column := range table.Columns {
+			oldColumn := old.column(column.Name)
+			if oldColumn == nil {
+				changes = append(changes, schemaChange{
+					up:   []string{alter + "ADD COLUMN " + quote(column.Name) + " " + column.Definition},
+					down: []string{alter + "DROP COLUMN " + quote(column.Name)},
+				})
+				continue
+			}
+			if oldColumn.Definition == column.Definition {
+				continue
+			}
+			if !postgres {
+				return nil, nil, fmt.Errorf("changing column %s.%s from %q to %q requires rebuilding the table on %s; write this migration by hand",
+					table.Name, column.Name, oldColumn.Definition, column.Definition, current.Dialect)
+			}
+			changes = append(changes, schemaChange{
+				up:   alterColumn(alter+"ALTER COLUMN "+quote(column.Name)+" ", *oldColumn, column),
+				down: alterColumn(alter+"ALTER COLUMN "+quote(column.Name)+" ", column, *oldColumn),
+			})
+		}
+
+		for _, column := range old.Columns {
+			if table.column(column.Name) == nil {
+				changes = append(changes, schemaChange{
+					up:   []string{alter + "DROP COLUMN " + quote(column.Name)},
+					down: []string{alter + "ADD COLUMN " + quote(column.Name) + " " + column.Definition},
+				})
+			}
+		}
+
+		for _, idx := range table.Indexes {
+			if old := old.index(idx.Name); old == nil || old.Create != idx.Create {
+				changes = append(changes, schemaChange{up: []string{idx.Create}, down: []string{"DROP INDEX " + quote(idx.Name)}})
+			}
+		}
+	}
+
+	// Drop removed tables in reverse creation order
+	for i := len(previous.Tables) - 1; i >= 0; i-- {
+		table := previous.Tables[i]
+		if current.table(table.Name) != nil {
+			continue
+		}
+		change := schemaChange{up: []string{"DROP TABLE " + quote(table.Name)}, down: append([]string(nil), table.Create...)}
+		for _, idx := range table.Indexes {
+			change.down = append(change.down, idx.Create)
+		}
+		changes = append(changes, change)
+	}
+
+	var up, down []string
+	for i, change := range changes {
+		up = append(up, change.up...)
+		down = append(down, changes[len(changes)-1-i].down...)
+	}
+	return up, down, nil
+}
+
+// alterColumn returns the PostgreSQL statements changing a column from one
+// definition to another; prefix is "ALTER TABLE t ALTER COLUMN c ".
+func alterColumn(prefix string, from, to columnState) []string {
+	var statements []string
+	if from.Type != to.Type {
+		statements = append(statements, prefix+"TYPE "+to.Type)
+	}
+	if from.NotNull != to.NotNull {
+		if to.NotNull {
+			statements = append(statements, prefix+"SET NOT NULL")
+		} else {
+			statements = append(statements, prefix+"DROP NOT NULL")
+		}
+	}
+	if from.Default != to.Default {
+		if to.Default == "" {
+			statements = append(statements, prefix+"DROP DEFAULT")
+		} else {
+			statements = append(statements, prefix+"SET DEFAULT "+to.Default)
+		}
+	}
+	return statements
+}
+
+// readSchemaState reads a state file, returning an empty state if it does
+// not exist yet.
+func readSchemaState(path string) (*schemaState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &schemaState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &schemaState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// writeSchemaState writes a state file.
+func writeSchemaState(path string, state *schemaState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// nextMigrationNumber returns the number of the next migration in dir.
+func nextMigrationNumber(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	last := 0
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		if number, err := strconv.Atoi(match[1]); err == nil && number > last {
+			last = number
+		}
+	}
+	return last + 1, nil
+}
+
+// sqlMigrationSource renders a migration as a SQL migration file.
+func sqlMigrationSource(migration Migration) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "-- Migration %s generated by gobase makemigrations.\n", migration.Name)
+	for _, section := range []struct {
+		marker     string
+		statements []string
+	}{
+		{migrationUpMarker, migration.Up},
+		{migrationDownMarker, migration.Down},
+	} {
+		fmt.Fprintf(&buf, "\n%s\n", section.marker)
+		for _, statement := range section.statements {
+			fmt.Fprintf(&buf, "%s;\n", statement)
+		}
+	}
+	return buf.Bytes()
+}
+
+// goMigrationSource renders a migration as a Go file registering it.
+func goMigrationSource(migration Migration, pkg string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Migration %s generated by gobase makemigrations.\n\n", migration.Name)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import \"github.com/AIGamer28100/gobase\"\n\n")
+	buf.WriteString("func init() {\n\tgobase.RegisterMigration(gobase.Migration{\n")
+	fmt.Fprintf(&buf, "Name: %q,\n", migration.Name)
+	for _, section := range []struct {
+		field      string
+		statements []string
+	}{
+		{"Up", migration.Up},
+		{"Down", migration.Down},
+	} {
+		fmt.Fprintf(&buf, "%s: []string{\n", section.field)
+		for _, statement := range section.statements {
+			fmt.Fprintf(&buf, "%s,\n", strconv.Quote(statement))
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("})\n}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format migration %s: %w", migration.Name, err)
+	}
+	return source, nil
+}
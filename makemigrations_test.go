@@ -0,0 +1,175 @@
+package gobase
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// InventoryItemV1 is the first version of a model evolved by the tests
+type InventoryItemV1 struct {
+	BaseModel
+	Name  string
+	Notes string
+}
+
+func (InventoryItemV1) TableName() string { return "inventory_items" }
+
+// InventoryItemV2 adds an indexed column and drops Notes
+type InventoryItemV2 struct {
+	BaseModel
+	Name     string
+	SKU      string `gorm:"uniqueIndex"`
+	Quantity int
+}
+
+func (InventoryItemV2) TableName() string { return "inventory_items" }
+
+// InventoryItemV3 changes the type of Quantity
+type InventoryItemV3 struct {
+	BaseModel
+	Name     string
+	SKU      string `gorm:"uniqueIndex"`
+	Quantity string
+}
+
+func (InventoryItemV3) TableName() string { return "inventory_items" }
+
+// TestAccessor_MakeMigrations tests generating and applying migrations as models evolve
+func TestAccessor_MakeMigrations(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	dir := filepath.Join(t.TempDir(), "migrations")
+
+	path, err := accessor.MakeMigrations(dir, MakeMigrationsOptions{}, &InventoryItemV1{})
+	if err != nil {
+		t.Fatalf("MakeMigrations failed: %v", err)
+	}
+	if filepath.Base(path) != "0001_initial.sql" {
+		t.Errorf("Expected 0001_initial.sql, got %s", path)
+	}
+	if _, err := os.Stat(filepath.Join(dir, MigrationStateFile)); err != nil {
+		t.Errorf("Expected state file to be written: %v", err)
+	}
+
+	// Unchanged models produce no migration
+	path, err = accessor.MakeMigrations(dir, MakeMigrationsOptions{}, &InventoryItemV1{})
+	if err != nil {
+		t.Fatalf("MakeMigrations failed: %v", err)
+	}
+	if path != "" {
+		t.Errorf("Expected no migration, got %s", path)
+	}
+
+	path, err = accessor.MakeMigrations(dir, MakeMigrationsOptions{Name: "add_sku"}, &InventoryItemV2{})
+	if err != nil {
+		t.Fatalf("MakeMigrations failed: %v", err)
+	}
+	if filepath.Base(path) != "0002_add_sku.sql" {
+		t.Errorf("Expected 0002_add_sku.sql, got %s", path)
+	}
+
+	migrations, err := LoadMigrations(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("LoadMigrations failed: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+	}
+
+	up := strings.Join(migrations[1].Up, "\n")
+	for _, expected := range []string{"ADD COLUMN `sku`", "ADD COLUMN `quantity`", "DROP COLUMN `notes`", "CREATE UNIQUE INDEX `idx_inventory_items_sku`"} {
+		if !strings.Contains(up, expected) {
+			t.Errorf("Expected Up to contain %q, got:\n%s", expected, up)
+		}
+	}
+	down := strings.Join(migrations[1].Down, "\n")
+	for _, expected := range []string{"ADD COLUMN `notes`", "DROP COLUMN `sku`", "DROP INDEX `idx_inventory_items_sku`"} {
+		if !strings.Contains(down, expected) {
+			t.Errorf("Expected Down to contain %q, got:\n%s", expected, down)
+		}
+	}
+
+	if _, err := accessor.ApplyMigrations(migrations); err != nil {
+		t.Fatalf("ApplyMigrations failed: %v", err)
+	}
+	if err := accessor.Create(&InventoryItemV2{Name: "Widget", SKU: "W-1", Quantity: 3}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := accessor.Create(&InventoryItemV2{Name: "Copy", SKU: "W-1"}); err == nil {
+		t.Error("Expected the unique index to be created")
+	}
+	if accessor.connection.GormDB.Migrator().HasColumn(&InventoryItemV1{}, "notes") {
+		t.Error("Expected notes column to be dropped")
+	}
+
+	// Changing a column type needs a table rebuild on SQLite
+	if _, err := accessor.MakeMigrations(dir, MakeMigrationsOptions{}, &InventoryItemV3{}); err == nil || !strings.Contains(err.Error(), "by hand") {
+		t.Errorf("Expected error for column type change, got %v", err)
+	}
+}
+
+// TestAccessor_MakeMigrationsGo tests generating Go migration files
+func TestAccessor_MakeMigrationsGo(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	dir := filepath.Join(t.TempDir(), "schema")
+
+	path, err := accessor.MakeMigrations(dir, MakeMigrationsOptions{Format: MigrationFormatGo}, &InventoryItemV1{})
+	if err != nil {
+		t.Fatalf("MakeMigrations failed: %v", err)
+	}
+	if filepath.Base(path) != "0001_initial.go" {
+		t.Errorf("Expected 0001_initial.go, got %s", path)
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		t.Fatalf("Expected valid Go source: %v", err)
+	}
+	if file.Name.Name != "schema" {
+		t.Errorf("Expected package schema, got %s", file.Name.Name)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read migration: %v", err)
+	}
+	for _, expected := range []string{"gobase.RegisterMigration(", `Name: "0001_initial"`, "CREATE TABLE `inventory_items`"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected migration to contain %q", expected)
+		}
+	}
+}
+
+// TestAccessor_MakeMigrationsErrors tests invalid options
+func TestAccessor_MakeMigrationsErrors(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		options MakeMigrationsOptions
+	}{
+		{name: "Unknown format", options: MakeMigrationsOptions{Format: "yaml"}},
+		{name: "Invalid name", options: MakeMigrationsOptions{Name: "add-sku"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := accessor.MakeMigrations(dir, tt.options, &InventoryItemV1{}); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+
+	// State generated for another database is rejected
+	state := []byte(`{"dialect": "postgres", "tables": []}`)
+	if err := os.WriteFile(filepath.Join(dir, MigrationStateFile), state, 0o644); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+	if _, err := accessor.MakeMigrations(dir, MakeMigrationsOptions{}, &InventoryItemV1{}); err == nil || !strings.Contains(err.Error(), "postgres") {
+		t.Errorf("Expected dialect mismatch error, got %v", err)
+	}
+}
@@ -0,0 +1,347 @@
+package gobase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Manager is a type-safe entry point for working with a single model type,
+// similar to Django's Model.objects. Where Accessor takes interface{} and
+// reports type mistakes at runtime, Manager methods take and return T, so
+// they are checked by the compiler.
+//
+//	articles := gobase.For[Article](accessor)
+//	article, err := articles.Get(1)
+//	published, err := articles.Filter(gobase.Q{"status": "published"})
+//
+// On backends without QuerySets, GetBy, Filter, First, Count and Exists
+// accept Q conditions, which are run as Accessor filter maps.
+type Manager[T any] struct {
+	accessor *Accessor
+	err      error
+}
+
+// For returns a Manager for the model type T. T is validated once here; if
+// it does not embed BaseModel every Manager method returns the validation
+// error.
+func For[T any](accessor *Accessor) *Manager[T] {
+	m := &Manager[T]{accessor: accessor}
+	if err := ValidateBaseModel(new(T)); err != nil {
+		m.err = fmt.Errorf("model validation failed: %w", err)
+	}
+	return m
+}
+
+// WithContext returns a copy of the Manager whose operations run with the
+// given context.
+func (m *Manager[T]) WithContext(ctx context.Context) *Manager[T] {
+	return &Manager[T]{accessor: m.accessor.WithContext(ctx), err: m.err}
+}
+
+// WithDeleted returns a copy of the Manager whose reads include
+// soft-deleted records.
+func (m *Manager[T]) WithDeleted() *Manager[T] {
+	return &Manager[T]{accessor: m.accessor.WithDeleted(), err: m.err}
+}
+
+// OnlyDeleted returns a copy of the Manager whose reads only return
+// soft-deleted records.
+func (m *Manager[T]) OnlyDeleted() *Manager[T] {
+	return &Manager[T]{accessor: m.accessor.OnlyDeleted(), err: m.err}
+}
+
+// Err returns the validation error for T, if any.
+func (m *Manager[T]) Err() error {
+	return m.err
+}
+
+// filterMap merges conditions into a single filter map when the
+// connection's backend has no QuerySets and every condition is a Q, so
+// the query can run through Accessor.Filter and CountFilter instead.
+func (m *Manager[T]) filterMap(conditions []Condition) (map[string]interface{}, bool) {
+	if _, ok := m.accessor.queryCompiler(); ok {
+		return nil, false
+	}
+	return conditionMap(conditions)
+}
+
+// filter retrieves the records matching conditions through the
+// Accessor's filter maps.
+func (m *Manager[T]) filter(conditions map[string]interface{}) ([]T, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var models []T
+	if err := m.accessor.Filter(&models, conditions); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// Objects returns a QuerySet over T for building more complex queries.
+func (m *Manager[T]) Objects() *QuerySet {
+	return m.accessor.Objects(new(T))
+}
+
+// Get retrieves the record with the given ID.
+func (m *Manager[T]) Get(id interface{}) (*T, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	model := new(T)
+	if err := m.accessor.Get(model, id); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// GetBy retrieves the single record matching the given conditions. It
+// returns ErrDoesNotExist or ErrMultipleObjectsReturned when there is not
+// exactly one match.
+func (m *Manager[T]) GetBy(conditions ...Condition) (*T, error) {
+	if filter, ok := m.filterMap(conditions); ok {
+		models, err := m.filter(filter)
+		if err != nil {
+			return nil, err
+		}
+		switch len(models) {
+		case 0:
+			return nil, ErrDoesNotExist
+		case 1:
+			return &models[0], nil
+		default:
+			return nil, ErrMultipleObjectsReturned
+		}
+	}
+
+	model := new(T)
+	if err := m.Objects().Filter(conditions...).Get(model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// All retrieves all records.
+func (m *Manager[T]) All() ([]T, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var models []T
+	if err := m.accessor.All(&models); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// Filter retrieves the records matching all of the given conditions.
+func (m *Manager[T]) Filter(conditions ...Condition) ([]T, error) {
+	if filter, ok := m.filterMap(conditions); ok {
+		return m.filter(filter)
+	}
+
+	var models []T
+	if err := m.Objects().Filter(conditions...).All(&models); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// Exclude retrieves the records not matching the given conditions.
+func (m *Manager[T]) Exclude(conditions ...Condition) ([]T, error) {
+	var models []T
+	if err := m.Objects().Exclude(conditions...).All(&models); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// First retrieves the first record, by primary key, matching the given
+// conditions.
+func (m *Manager[T]) First(conditions ...Condition) (*T, error) {
+	if filter, ok := m.filterMap(conditions); ok {
+		models, err := m.filter(filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(models) == 0 {
+			return nil, ErrDoesNotExist
+		}
+		sortByID(models)
+		return &models[0], nil
+	}
+
+	model := new(T)
+	if err := m.Objects().Filter(conditions...).First(model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// Count returns the number of records matching the given conditions.
+func (m *Manager[T]) Count(conditions ...Condition) (int64, error) {
+	if filter, ok := m.filterMap(conditions); ok {
+		if m.err != nil {
+			return 0, m.err
+		}
+		return m.accessor.CountFilter(new(T), filter)
+	}
+
+	return m.Objects().Filter(conditions...).Count()
+}
+
+// Exists reports whether any record matches the given conditions.
+func (m *Manager[T]) Exists(conditions ...Condition) (bool, error) {
+	if _, ok := m.filterMap(conditions); ok {
+		count, err := m.Count(conditions...)
+		return count > 0, err
+	}
+
+	return m.Objects().Filter(conditions...).Exists()
+}
+
+// GetOrCreate retrieves the single record matching lookup, creating it from
+// lookup and defaults if it does not exist. See Accessor.GetOrCreate.
+func (m *Manager[T]) GetOrCreate(lookup, defaults map[string]interface{}) (*T, bool, error) {
+	if m.err != nil {
+		return nil, false, m.err
+	}
+
+	model := new(T)
+	created, err := m.accessor.GetOrCreate(model, lookup, defaults)
+	if err != nil {
+		return nil, false, err
+	}
+	return model, created, nil
+}
+
+// UpdateOrCreate updates the single record matching lookup with defaults,
+// creating it if it does not exist. See Accessor.UpdateOrCreate.
+func (m *Manager[T]) UpdateOrCreate(lookup, defaults map[string]interface{}) (*T, bool, error) {
+	if m.err != nil {
+		return nil, false, m.err
+	}
+
+	model := new(T)
+	created, err := m.accessor.UpdateOrCreate(model, lookup, defaults)
+	if err != nil {
+		return nil, false, err
+	}
+	return model, created, nil
+}
+
+// BulkCreate inserts models in batches of up to batchSize records and
+// returns the number of records inserted. See Accessor.BulkCreate.
+func (m *Manager[T]) BulkCreate(models []T, batchSize int) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.accessor.BulkCreate(models, batchSize)
+}
+
+// BulkUpdate saves the given fields of models and returns the number of
+// records updated. See Accessor.BulkUpdate.
+func (m *Manager[T]) BulkUpdate(models []T, fields ...string) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.accessor.BulkUpdate(models, fields...)
+}
+
+// Paginate retrieves the requested page of records matching the given
+// conditions. See QuerySet.Paginate.
+func (m *Manager[T]) Paginate(page, pageSize int, conditions ...Condition) ([]T, *Page, error) {
+	var models []T
+	p, err := m.Objects().Filter(conditions...).Paginate(&models, page, pageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return models, p, nil
+}
+
+// CursorPaginate retrieves the page of records following cursor, oldest
+// first, among those matching the given conditions. See
+// QuerySet.CursorPaginate.
+func (m *Manager[T]) CursorPaginate(cursor string, pageSize int, conditions ...Condition) ([]T, *CursorPage, error) {
+	var models []T
+	p, err := m.Objects().Filter(conditions...).CursorPaginate(&models, cursor, pageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return models, p, nil
+}
+
+// Create inserts a new record.
+func (m *Manager[T]) Create(model *T) error {
+	if m.err != nil {
+		return m.err
+	}
+	return m.accessor.Create(model)
+}
+
+// Update saves changes to an existing record.
+func (m *Manager[T]) Update(model *T) error {
+	if m.err != nil {
+		return m.err
+	}
+	return m.accessor.Update(model)
+}
+
+// Delete soft-deletes the record.
+func (m *Manager[T]) Delete(model *T) error {
+	if m.err != nil {
+		return m.err
+	}
+	return m.accessor.Delete(model)
+}
+
+// Restore undeletes a soft-deleted record.
+func (m *Manager[T]) Restore(model *T) error {
+	if m.err != nil {
+		return m.err
+	}
+	return m.accessor.Restore(model)
+}
+
+// HardDelete permanently removes the record.
+func (m *Manager[T]) HardDelete(model *T) error {
+	if m.err != nil {
+		return m.err
+	}
+	return m.accessor.HardDelete(model)
+}
+
+// PurgeDeleted permanently removes records soft-deleted more than olderThan
+// ago and returns the number removed.
+func (m *Manager[T]) PurgeDeleted(olderThan time.Duration) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.accessor.PurgeDeleted(new(T), olderThan)
+}
+
+// sortByID orders models by their integer ID field, leaving models with
+// other primary keys in the order the backend returned them.
+func sortByID[T any](models []T) {
+	idField, ok := reflect.TypeOf(models).Elem().FieldByName("ID")
+	if !ok {
+		return
+	}
+	switch idField.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sort.SliceStable(models, func(i, j int) bool {
+			return reflect.ValueOf(models[i]).FieldByIndex(idField.Index).Int() <
+				reflect.ValueOf(models[j]).FieldByIndex(idField.Index).Int()
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		sort.SliceStable(models, func(i, j int) bool {
+			return reflect.ValueOf(models[i]).FieldByIndex(idField.Index).Uint() <
+				reflect.ValueOf(models[j]).FieldByIndex(idField.Index).Uint()
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package gobase
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestManager_CRUD tests the typed Manager API
+func TestManager_CRUD(t *testing.T) {
+	accessor := setupArticles(t)
+	articles := For[Article](accessor)
+
+	if err := articles.Err(); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	article := &Article{Title: "Typed", Author: "dave", Status: "draft"}
+	if err := articles.Create(article); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	got, err := articles.Get(article.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Title != "Typed" {
+		t.Errorf("Expected title 'Typed', got %s", got.Title)
+	}
+
+	got.Views = 99
+	if err := articles.Update(got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	all, err := articles.All()
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(all) != 5 {
+		t.Errorf("Expected 5 articles, got %d", len(all))
+	}
+
+	popular, err := articles.Filter(Q{"views__gte": 50})
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if len(popular) != 2 {
+		t.Errorf("Expected 2 popular articles, got %d", len(popular))
+	}
+
+	others, err := articles.Exclude(Q{"author": "alice"})
+	if err != nil {
+		t.Fatalf("Exclude failed: %v", err)
+	}
+	if len(others) != 3 {
+		t.Errorf("Expected 3 articles not by alice, got %d", len(others))
+	}
+
+	first, err := articles.First(Q{"author": "alice"})
+	if err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if first.Title != "Go Basics" {
+		t.Errorf("Expected 'Go Basics', got %s", first.Title)
+	}
+
+	if err := articles.Delete(got); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	count, err := articles.Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("Expected 4 articles after delete, got %d", count)
+	}
+
+	exists, err := articles.Exists(Q{"author": "dave"})
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if exists {
+		t.Error("Expected deleted article to be hidden")
+	}
+}
+
+// TestManager_MemoryBackend tests Manager lookups on a backend without QuerySets
+func TestManager_MemoryBackend(t *testing.T) {
+	articles := For[Article](setupMemoryArticles(t))
+
+	popular, err := articles.Filter(Q{"views__gte": 30}, Q{"status": "published"})
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if len(popular) != 2 {
+		t.Errorf("Expected 2 popular articles, got %d", len(popular))
+	}
+
+	article, err := articles.GetBy(Q{"title": "Draft Notes"})
+	if err != nil {
+		t.Fatalf("GetBy failed: %v", err)
+	}
+	if article.Author != "alice" {
+		t.Errorf("Expected author alice, got %s", article.Author)
+	}
+	if _, err := articles.GetBy(Q{"author": "alice"}); !errors.Is(err, ErrMultipleObjectsReturned) {
+		t.Errorf("Expected ErrMultipleObjectsReturned, got %v", err)
+	}
+	if _, err := articles.GetBy(Q{"author": "dave"}); !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Expected ErrDoesNotExist, got %v", err)
+	}
+
+	first, err := articles.First(Q{"author": "alice"})
+	if err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if first.Title != "Go Basics" {
+		t.Errorf("Expected 'Go Basics', got %s", first.Title)
+	}
+
+	count, err := articles.Count(Q{"status": "published"})
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 published articles, got %d", count)
+	}
+
+	exists, err := articles.Exists(Q{"author": "dave"})
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if exists {
+		t.Error("Expected no articles by dave")
+	}
+
+	// Conditions a filter map cannot express still need QuerySets
+	if _, err := articles.Filter(Q{"author": "alice"}.Or(Q{"author": "bob"})); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+	if _, err := articles.Exclude(Q{"author": "alice"}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+	if _, _, err := articles.Paginate(1, 10); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+}
+
+// TestManager_InvalidModel tests that invalid model types fail on every call
+func TestManager_InvalidModel(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+
+	manager := For[NotAModel](accessor)
+	if manager.Err() == nil {
+		t.Fatal("Expected validation error at construction")
+	}
+
+	if _, err := manager.All(); err == nil {
+		t.Error("Expected error from All")
+	}
+	if _, err := manager.Get(1); err == nil {
+		t.Error("Expected error from Get")
+	}
+	if _, err := manager.Filter(Q{"name": "x"}); err == nil {
+		t.Error("Expected error from Filter")
+	}
+	if err := manager.Create(&NotAModel{}); err == nil {
+		t.Error("Expected error from Create")
+	}
+}
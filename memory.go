@@ -0,0 +1,558 @@
+package gobase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// memoryDBType is the database type of in-memory connections.
+const memoryDBType = "memory"
+
+// memorySchemaCache caches the schemas of models stored in memory.
+var memorySchemaCache sync.Map
+
+// memoryNamer names tables and columns like GORM, so constraint names
+// match those of the SQL databases.
+var memoryNamer = schema.NamingStrategy{IdentifierMaxLength: 64}
+
+// memoryBackend is a Backend keeping records in memory, for fast tests of
+// code written against an Accessor. Each backend is a separate database,
+// empty until Migrate creates its tables.
+//
+// Transactions work on a copy of the tables taken when they begin, without
+// locking the backend, and replace the tables they changed when they
+// commit. A commit fails if one of those tables changed in the meantime.
+type memoryBackend struct {
+	mu     sync.Mutex
+	tables map[string]*memoryTable
+}
+
+// memoryTable holds the records of a model, in insertion order, as struct
+// values. Records are never modified in place: writes replace them, so
+// transactions can share them with the tables they copy.
+type memoryTable struct {
+	schema      *schema.Schema
+	constraints []memoryConstraint
+	rows        []reflect.Value
+	nextID      int64
+	version     int64 // incremented by every write
+}
+
+// memoryConstraint is a unique index enforced by the memory backend.
+type memoryConstraint struct {
+	name   string
+	fields []*schema.Field
+}
+
+// NewMemoryBackend returns a new, empty in-memory database. Connections
+// with DB_TYPE=memory use one each:
+//
+//	accessor := gobase.NewAccessor(gobase.NewConnection("memory", gobase.NewMemoryBackend()))
+func NewMemoryBackend() Backend {
+	return &memoryBackend{tables: map[string]*memoryTable{}}
+}
+
+// initMemory opens an in-memory database. The configuration is not used.
+func initMemory(*DatabaseConfig) (Backend, error) {
+	return NewMemoryBackend(), nil
+}
+
+// memorySchema parses the schema of a model stored in memory.
+func memorySchema(model interface{}) (*schema.Schema, error) {
+	s, err := schema.Parse(model, &memorySchemaCache, memoryNamer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model schema: %w", err)
+	}
+	if s.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("model '%s' has no primary key", s.Name)
+	}
+	return s, nil
+}
+
+// memoryModel returns the schema and addressable struct value of model,
+// which must be a pointer.
+func memoryModel(model interface{}) (*schema.Schema, reflect.Value, error) {
+	rv := reflect.ValueOf(model)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, reflect.Value{}, errors.New("model must be a non-nil pointer")
+	}
+	s, err := memorySchema(model)
+	if err != nil {
+		return nil, reflect.Value{}, err
+	}
+	return s, rv.Elem(), nil
+}
+
+// memoryConstraints returns the unique indexes declared in the model's
+// gorm tags: the primary key, uniqueIndex and unique fields.
+func memoryConstraints(s *schema.Schema) []memoryConstraint {
+	constraints := []memoryConstraint{{fields: s.PrimaryFields}}
+	for _, index := range s.ParseIndexes() {
+		if index.Class != "UNIQUE" {
+			continue
+		}
+		constraint := memoryConstraint{name: index.Name}
+		for _, option := range index.Fields {
+			constraint.fields = append(constraint.fields, option.Field)
+		}
+		constraints = append(constraints, constraint)
+	}
+
+	for _, field := range s.Fields {
+		if field.Unique && !field.PrimaryKey && field.DBName != "" {
+			constraints = append(constraints, memoryConstraint{
+				name:   memoryNamer.UniqueName(s.Table, field.DBName),
+				fields: []*schema.Field{field},
+			})
+		}
+	}
+	return constraints
+}
+
+// memoryCopy returns an addressable copy of the struct value rv.
+func memoryCopy(rv reflect.Value) reflect.Value {
+	row := reflect.New(rv.Type()).Elem()
+	row.Set(rv)
+	return row
+}
+
+// memoryNow returns the current time without its monotonic clock reading,
+// like the times read back from a database.
+func memoryNow() time.Time {
+	return time.Now().Round(0)
+}
+
+// lock locks the backend for an operation, unless ctx is already done.
+func (b *memoryBackend) lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	return nil
+}
+
+// table returns the table storing the model's records, which Migrate must
+// have created.
+func (b *memoryBackend) table(s *schema.Schema) (*memoryTable, error) {
+	t, ok := b.tables[s.Table]
+	if !ok {
+		return nil, fmt.Errorf("no such table: %s", s.Table)
+	}
+	if t.schema.ModelType != s.ModelType {
+		return nil, fmt.Errorf("table %s holds %s records, not %s", s.Table, t.schema.Name, s.Name)
+	}
+	return t, nil
+}
+
+// index returns the position of the record whose primary key is id, or
+// -1 if there is none.
+func (t *memoryTable) index(ctx context.Context, id interface{}) int {
+	field := t.schema.PrioritizedPrimaryField
+	for i, row := range t.rows {
+		value, _ := field.ValueOf(ctx, row)
+		if memoryEqual(memoryValue(value), memoryValue(id)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// matches returns the records selected by conditions in the soft-delete
+// scope.
+func (t *memoryTable) matches(ctx context.Context, conditions []memoryCondition, scope DeletedScope) []reflect.Value {
+	var rows []reflect.Value
+	for _, row := range t.rows {
+		if memoryDeleted(ctx, t.schema, row, scope) && memoryMatch(ctx, conditions, row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// checkUnique returns an IntegrityError if row violates a unique index,
+// ignoring the record at position skip, which row replaces. Like SQL
+// databases, indexes ignore rows with NULL keys and include soft-deleted
+// records.
+func (t *memoryTable) checkUnique(ctx context.Context, row reflect.Value, skip int) error {
+	for _, constraint := range t.constraints {
+		key := memoryKey(ctx, constraint.fields, row)
+		if key == nil {
+			continue
+		}
+		for i, other := range t.rows {
+			if i != skip && slices.EqualFunc(key, memoryKey(ctx, constraint.fields, other), memoryEqual) {
+				names := make([]string, len(constraint.fields))
+				for j, field := range constraint.fields {
+					names[j] = field.DBName
+				}
+				return &IntegrityError{
+					Kind:       ErrUniqueViolation,
+					Table:      t.schema.Table,
+					Field:      strings.Join(names, ","),
+					Constraint: constraint.name,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// memoryKey returns the values of fields in row, or nil if one is NULL.
+func memoryKey(ctx context.Context, fields []*schema.Field, row reflect.Value) []interface{} {
+	key := make([]interface{}, len(fields))
+	for i, field := range fields {
+		value, _ := field.ValueOf(ctx, row)
+		if key[i] = memoryValue(value); key[i] == nil {
+			return nil
+		}
+	}
+	return key
+}
+
+// memoryDeleted reports whether row is in the soft-delete scope.
+func memoryDeleted(ctx context.Context, s *schema.Schema, row reflect.Value, scope DeletedScope) bool {
+	field := deletedAtField(s)
+	if field == nil || scope == IncludeDeleted {
+		return field != nil || scope != OnlyDeleted
+	}
+	value, _ := field.ValueOf(ctx, row)
+	return (memoryValue(value) != nil) == (scope == OnlyDeleted)
+}
+
+// insert adds row to the table, assigning its primary key: the next value
+// of the table's sequence for integer IDs left zero.
+func (t *memoryTable) insert(ctx context.Context, row reflect.Value) error {
+	field := t.schema.PrioritizedPrimaryField
+	kind := field.IndirectFieldType.Kind()
+	isInteger := kind >= reflect.Int && kind <= reflect.Uint64
+
+	var id int64
+	if value, zero := field.ValueOf(ctx, row); isInteger && zero {
+		id = t.nextID + 1
+		if err := field.Set(ctx, row, id); err != nil {
+			return err
+		}
+	} else if isInteger {
+		id = memoryInt(reflect.Indirect(reflect.ValueOf(value)))
+	}
+
+	if err := t.checkUnique(ctx, row, -1); err != nil {
+		return err
+	}
+	if isInteger {
+		t.nextID = max(t.nextID, id)
+	}
+	t.rows = append(t.rows, row)
+	t.version++
+	return nil
+}
+
+// Create implements Backend.
+func (b *memoryBackend) Create(ctx context.Context, model interface{}) error {
+	s, rv, err := memoryModel(model)
+	if err != nil {
+		return err
+	}
+	if err := b.lock(ctx); err != nil {
+		return err
+	}
+	defer b.mu.Unlock()
+	t, err := b.table(s)
+	if err != nil {
+		return err
+	}
+
+	row := memoryCopy(rv)
+	now := memoryNow()
+	for _, field := range s.Fields {
+		if field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
+			if _, zero := field.ValueOf(ctx, row); zero {
+				timeType := max(field.AutoCreateTime, field.AutoUpdateTime)
+				if err := field.Set(ctx, row, autoTimestamp(field, now, timeType)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := t.insert(ctx, row); err != nil {
+		return err
+	}
+
+	rv.Set(row)
+	return nil
+}
+
+// Get implements Backend.
+func (b *memoryBackend) Get(ctx context.Context, model interface{}, id interface{}, query Query) error {
+	s, rv, err := memoryModel(model)
+	if err != nil {
+		return err
+	}
+	conditions, err := compileMemoryQuery(s, query)
+	if err != nil {
+		return err
+	}
+	if err := b.lock(ctx); err != nil {
+		return err
+	}
+	defer b.mu.Unlock()
+	t, err := b.table(s)
+	if err != nil {
+		return err
+	}
+
+	i := t.index(ctx, id)
+	if i < 0 || !memoryDeleted(ctx, s, t.rows[i], query.Deleted) || !memoryMatch(ctx, conditions, t.rows[i]) {
+		return translateError(gorm.ErrRecordNotFound)
+	}
+	rv.Set(t.rows[i])
+	return nil
+}
+
+// Find implements Backend. Records are returned in insertion order.
+func (b *memoryBackend) Find(ctx context.Context, models interface{}, query Query) error {
+	rv := reflect.ValueOf(models)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("models must be a pointer to a slice")
+	}
+	s, err := memorySchema(models)
+	if err != nil {
+		return err
+	}
+	conditions, err := compileMemoryQuery(s, query)
+	if err != nil {
+		return err
+	}
+	if err := b.lock(ctx); err != nil {
+		return err
+	}
+	defer b.mu.Unlock()
+	t, err := b.table(s)
+	if err != nil {
+		return err
+	}
+
+	slice := rv.Elem()
+	records := reflect.MakeSlice(slice.Type(), 0, 0)
+	for _, row := range t.matches(ctx, conditions, query.Deleted) {
+		if slice.Type().Elem().Kind() == reflect.Ptr {
+			records = reflect.Append(records, memoryCopy(row).Addr())
+		} else {
+			records = reflect.Append(records, row)
+		}
+	}
+
+	slice.Set(records)
+	return nil
+}
+
+// Count implements Backend.
+func (b *memoryBackend) Count(ctx context.Context, model interface{}, query Query) (int64, error) {
+	s, err := memorySchema(model)
+	if err != nil {
+		return 0, err
+	}
+	conditions, err := compileMemoryQuery(s, query)
+	if err != nil {
+		return 0, err
+	}
+	if err := b.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer b.mu.Unlock()
+	t, err := b.table(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(t.matches(ctx, conditions, query.Deleted))), nil
+}
+
+// Update implements Backend. Like GORM's Save, it writes every field, and
+// creates the record when the model has no ID yet or its record does not
+// exist.
+func (b *memoryBackend) Update(ctx context.Context, model interface{}) error {
+	s, rv, err := memoryModel(model)
+	if err != nil {
+		return err
+	}
+	if _, zero := s.PrioritizedPrimaryField.ValueOf(ctx, rv); zero {
+		return b.Create(ctx, model)
+	}
+	if err := b.lock(ctx); err != nil {
+		return err
+	}
+	defer b.mu.Unlock()
+	t, err := b.table(s)
+	if err != nil {
+		return err
+	}
+
+	row := memoryCopy(rv)
+	now := memoryNow()
+	for _, field := range s.Fields {
+		if field.AutoUpdateTime > 0 {
+			if err := field.Set(ctx, row, autoTimestamp(field, now, field.AutoUpdateTime)); err != nil {
+				return err
+			}
+		}
+	}
+
+	id, _ := s.PrioritizedPrimaryField.ValueOf(ctx, row)
+	if i := t.index(ctx, id); i >= 0 {
+		if err := t.checkUnique(ctx, row, i); err != nil {
+			return err
+		}
+		t.rows[i] = row
+		t.version++
+	} else if err := t.insert(ctx, row); err != nil {
+		return err
+	}
+
+	rv.Set(row)
+	return nil
+}
+
+// Delete implements Backend: it sets deleted_at, or removes the record of
+// models without soft deletion.
+func (b *memoryBackend) Delete(ctx context.Context, model interface{}) error {
+	s, rv, err := memoryModel(model)
+	if err != nil {
+		return err
+	}
+	relations, err := onDeleteRelations(s)
+	if err != nil {
+		return err
+	}
+	if len(relations) > 0 {
+		return fmt.Errorf("%w: on_delete actions on the %s backend", ErrNotSupported, memoryDBType)
+	}
+	if err := b.lock(ctx); err != nil {
+		return err
+	}
+	defer b.mu.Unlock()
+	t, err := b.table(s)
+	if err != nil {
+		return err
+	}
+
+	id, _ := s.PrioritizedPrimaryField.ValueOf(ctx, rv)
+	i := t.index(ctx, id)
+	field := deletedAtField(s)
+	switch {
+	case i < 0 || !memoryDeleted(ctx, s, t.rows[i], ExcludeDeleted):
+		return nil
+	case field == nil:
+		t.rows = slices.Delete(t.rows, i, i+1)
+		t.version++
+		return nil
+	}
+
+	now := memoryNow()
+	row := memoryCopy(t.rows[i])
+	if err := field.Set(ctx, row, now); err != nil {
+		return err
+	}
+	t.rows[i] = row
+	t.version++
+	return field.Set(ctx, rv, now)
+}
+
+// Migrate implements Backend: it creates the models' tables, and enforces
+// the unique indexes declared in their gorm tags.
+func (b *memoryBackend) Migrate(ctx context.Context, models []interface{}) error {
+	if err := b.lock(ctx); err != nil {
+		return err
+	}
+	defer b.mu.Unlock()
+
+	for _, model := range models {
+		s, err := memorySchema(model)
+		if err != nil {
+			return err
+		}
+		t, ok := b.tables[s.Table]
+		if !ok {
+			t = &memoryTable{}
+			b.tables[s.Table] = t
+		}
+		t.schema, t.constraints = s, memoryConstraints(s)
+		t.version++
+	}
+	return nil
+}
+
+// Transaction implements Backend. fn runs on a copy of the tables, so
+// the backend stays usable while it runs, and the tables fn changed
+// replace the originals when it returns nil. Nested transactions therefore
+// roll back like savepoints.
+func (b *memoryBackend) Transaction(ctx context.Context, fn func(tx Backend) error) error {
+	if err := b.lock(ctx); err != nil {
+		return err
+	}
+	tx := &memoryBackend{tables: make(map[string]*memoryTable, len(b.tables))}
+	originals := maps.Clone(b.tables)
+	versions := make(map[string]int64, len(b.tables))
+	for name, t := range b.tables {
+		copied := *t
+		copied.rows = slices.Clone(t.rows)
+		tx.tables[name] = &copied
+		versions[name] = t.version
+	}
+	b.mu.Unlock()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	changed := make(map[string]*memoryTable)
+	for name, t := range tx.tables {
+		version, existed := versions[name]
+		if existed && t.version == version {
+			continue
+		}
+		// Like a serialization failure, rather than lose the other change
+		current, ok := b.tables[name]
+		if ok != existed || current != originals[name] || (ok && current.version != version) {
+			return fmt.Errorf("transaction conflict: table %s was changed outside the transaction", name)
+		}
+		changed[name] = t
+	}
+	maps.Copy(b.tables, changed)
+	return nil
+}
+
+// Tables implements Backend.
+func (b *memoryBackend) Tables(ctx context.Context) ([]string, error) {
+	if err := b.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer b.mu.Unlock()
+
+	names := make([]string, 0, len(b.tables))
+	for name := range b.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Close implements Backend. The records are kept until the backend is
+// garbage collected.
+func (b *memoryBackend) Close() error {
+	return nil
+}
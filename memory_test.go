@@ -0,0 +1,502 @@
+package gobase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupMemoryDB opens an empty in-memory database
+func setupMemoryDB(t *testing.T) *Connection {
+	connection, err := InitDBWithConfig(&DatabaseConfig{Type: memoryDBType})
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+	t.Cleanup(func() { connection.Close() })
+
+	return connection
+}
+
+// setupMemoryArticles creates a migrated in-memory database with a few articles
+func setupMemoryArticles(t *testing.T) *Accessor {
+	accessor := NewAccessor(setupMemoryDB(t))
+	if err := accessor.Migrate(&Article{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	articles := []*Article{
+		{Title: "Go Basics", Author: "alice", Status: "published", Views: 10},
+		{Title: "Advanced Go", Author: "bob", Status: "published", Views: 50},
+		{Title: "Draft Notes", Author: "alice", Status: "draft", Views: 0},
+		{Title: "Django Tips", Author: "carol", Status: "published", Views: 30},
+	}
+	for _, article := range articles {
+		if err := accessor.Create(article); err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+
+	return accessor
+}
+
+// TestMemory_CRUD tests creating, reading, updating and deleting records
+func TestMemory_CRUD(t *testing.T) {
+	accessor := setupMemoryArticles(t)
+
+	article := &Article{Title: "Testing", Author: "dave", Status: "draft"}
+	if err := accessor.Create(article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	if article.ID != 5 {
+		t.Errorf("Expected ID 5, got %d", article.ID)
+	}
+	if article.CreatedAt.IsZero() || article.UpdatedAt.IsZero() {
+		t.Error("Expected the timestamps to be set")
+	}
+
+	// Records are copies: changing the model does not change the database
+	article.Title = "Changed"
+	retrieved := &Article{}
+	if err := accessor.Get(retrieved, "5"); err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	if retrieved.Title != "Testing" {
+		t.Errorf("Expected title Testing, got %s", retrieved.Title)
+	}
+
+	retrieved.Views = 7
+	if err := accessor.Update(retrieved); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	updated := &Article{}
+	if err := accessor.Get(updated, 5); err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	if updated.Views != 7 || !updated.CreatedAt.Equal(article.CreatedAt) {
+		t.Errorf("Expected the updated article, got %+v", updated)
+	}
+
+	var articles []*Article
+	if err := accessor.All(&articles); err != nil {
+		t.Fatalf("Failed to get all articles: %v", err)
+	}
+	if len(articles) != 5 || articles[0].Title != "Go Basics" {
+		t.Errorf("Expected 5 articles in insertion order, got %d", len(articles))
+	}
+
+	if err := accessor.Delete(updated); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	if !updated.DeletedAt.Valid {
+		t.Error("Expected DeletedAt to be set")
+	}
+	if err := accessor.Get(&Article{}, 5); !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Expected ErrDoesNotExist, got %v", err)
+	}
+	if err := accessor.WithDeleted().Get(&Article{}, 5); err != nil {
+		t.Errorf("Expected WithDeleted to find the article, got %v", err)
+	}
+
+	count, err := accessor.OnlyDeleted().Count(&Article{}, "author = ?", "dave")
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 deleted article, got %d", count)
+	}
+}
+
+// TestMemory_FilterLookups tests field lookups against the memory backend
+func TestMemory_FilterLookups(t *testing.T) {
+	accessor := setupMemoryArticles(t)
+
+	old := &Article{Title: "100% Go.Lang", Author: "Dave", Status: "archived", Views: 5}
+	old.CreatedAt = time.Date(2020, time.March, 15, 12, 0, 0, 0, time.UTC)
+	if err := accessor.Create(old); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	deleted := &Article{Title: "Deleted Go", Author: "alice", Status: "published", Views: 99}
+	if err := accessor.Create(deleted); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	if err := accessor.Delete(deleted); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   int
+	}{
+		{name: "exact", conditions: map[string]interface{}{"author__exact": "alice"}, expected: 2},
+		{name: "iexact", conditions: map[string]interface{}{"author__iexact": "DAVE"}, expected: 1},
+		{name: "contains is case-sensitive", conditions: map[string]interface{}{"title__contains": "GO"}, expected: 0},
+		{name: "contains", conditions: map[string]interface{}{"title__contains": "Go"}, expected: 3},
+		{name: "icontains", conditions: map[string]interface{}{"title__icontains": "go"}, expected: 4},
+		{name: "startswith", conditions: map[string]interface{}{"title__startswith": "Go"}, expected: 1},
+		{name: "istartswith", conditions: map[string]interface{}{"title__istartswith": "d"}, expected: 2},
+		{name: "endswith", conditions: map[string]interface{}{"title__endswith": "Go"}, expected: 1},
+		{name: "iendswith", conditions: map[string]interface{}{"title__iendswith": "LANG"}, expected: 1},
+		{name: "gt", conditions: map[string]interface{}{"views__gt": 10}, expected: 2},
+		{name: "gte", conditions: map[string]interface{}{"views__gte": 10}, expected: 3},
+		{name: "lt", conditions: map[string]interface{}{"views__lt": 10}, expected: 2},
+		{name: "lte", conditions: map[string]interface{}{"views__lte": 10.5}, expected: 3},
+		{name: "in", conditions: map[string]interface{}{"author__in": []string{"bob", "carol"}}, expected: 2},
+		{name: "empty in", conditions: map[string]interface{}{"author__in": []string{}}, expected: 0},
+		{name: "range", conditions: map[string]interface{}{"views__range": []int{5, 30}}, expected: 3},
+		{name: "isnull", conditions: map[string]interface{}{"deleted_at__isnull": true}, expected: 5},
+		{name: "primary key", conditions: map[string]interface{}{"id__in": []int{1, 2}}, expected: 2},
+		{name: "year", conditions: map[string]interface{}{"created_at__year": 2020}, expected: 1},
+		{name: "year gte", conditions: map[string]interface{}{"created_at__year__gte": 2021}, expected: 4},
+		{name: "month", conditions: map[string]interface{}{"created_at__month": 3, "created_at__year": 2020}, expected: 1},
+		{name: "date", conditions: map[string]interface{}{"created_at__date": old.CreatedAt}, expected: 1},
+		{name: "date string", conditions: map[string]interface{}{"created_at__date": "2020-03-15"}, expected: 1},
+		{name: "combined", conditions: map[string]interface{}{"status": "published", "views__gt": 20}, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			err := accessor.Filter(&articles, tt.conditions)
+			if err != nil {
+				t.Fatalf("Filter failed: %v", err)
+			}
+
+			if len(articles) != tt.expected {
+				t.Errorf("Expected %d articles, got %d", tt.expected, len(articles))
+			}
+		})
+	}
+
+	count, err := accessor.OnlyDeleted().CountFilter(&Article{}, map[string]interface{}{"title__icontains": "go"})
+	if err != nil {
+		t.Fatalf("CountFilter failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 deleted article, got %d", count)
+	}
+}
+
+// TestMemory_FilterLookupErrors tests invalid lookups and values on the memory backend
+func TestMemory_FilterLookupErrors(t *testing.T) {
+	accessor := setupMemoryArticles(t)
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+	}{
+		{name: "unknown field", conditions: map[string]interface{}{"missing": 1}},
+		{name: "unknown lookup", conditions: map[string]interface{}{"views__between": 1}},
+		{name: "in without slice", conditions: map[string]interface{}{"views__in": 1}},
+		{name: "range with wrong length", conditions: map[string]interface{}{"views__range": []int{1}}},
+		{name: "isnull without bool", conditions: map[string]interface{}{"deleted_at__isnull": "yes"}},
+		{name: "contains without string", conditions: map[string]interface{}{"title__contains": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			if err := accessor.Filter(&articles, tt.conditions); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+// TestMemory_FindWhere tests raw conditions on the memory backend
+func TestMemory_FindWhere(t *testing.T) {
+	accessor := setupMemoryArticles(t)
+
+	tests := []struct {
+		name        string
+		condition   string
+		args        []interface{}
+		expected    int
+		expectError string
+	}{
+		{name: "Equal", condition: "author = ?", args: []interface{}{"alice"}, expected: 2},
+		{name: "Not equal", condition: "author <> ?", args: []interface{}{"alice"}, expected: 2},
+		{name: "Comparison", condition: "views >= ?", args: []interface{}{30}, expected: 2},
+		{name: "And", condition: "status = ? AND views > ?", args: []interface{}{"published", 20}, expected: 2},
+		{name: "In", condition: "author IN ?", args: []interface{}{[]string{"bob", "carol"}}, expected: 2},
+		{name: "Not in", condition: "author NOT IN (?)", args: []interface{}{[]string{"bob", "carol"}}, expected: 2},
+		{name: "Like", condition: "title LIKE ?", args: []interface{}{"%go%"}, expected: 3},
+		{name: "Is null", condition: "deleted_at IS NULL", expected: 4},
+		{name: "Is not null", condition: "deleted_at is not null", expected: 0},
+		{name: "Or", condition: "author = ? OR views > ?", args: []interface{}{"alice", 20}, expectError: "operation not supported"},
+		{name: "Missing argument", condition: "views > ?", expectError: "missing argument"},
+		{name: "Too many arguments", condition: "views > ?", args: []interface{}{1, 2}, expectError: "too many arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			err := accessor.FindWhere(&articles, tt.condition, tt.args...)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error %v, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindWhere failed: %v", err)
+			}
+
+			if len(articles) != tt.expected {
+				t.Errorf("Expected %d articles, got %d", tt.expected, len(articles))
+			}
+		})
+	}
+}
+
+// TestMemory_UniqueViolation tests enforcing the unique indexes of gorm tags
+func TestMemory_UniqueViolation(t *testing.T) {
+	accessor := NewAccessor(setupMemoryDB(t))
+	if err := accessor.Migrate(&User{}, &Gadget{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	alice := &User{Username: "alice", Email: "alice@example.com", PasswordHash: "x"}
+	if err := accessor.Create(alice); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := accessor.Delete(alice); err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		model      interface{}
+		field      string
+		constraint string
+	}{
+		{
+			name:       "Unique index on a soft-deleted record",
+			model:      &User{Username: "alice", Email: "other@example.com", PasswordHash: "x"},
+			field:      "username",
+			constraint: "idx_users_username",
+		},
+		{
+			name:  "Primary key",
+			model: &User{BaseModel: BaseModel{ID: alice.ID}, Username: "bob", Email: "bob@example.com", PasswordHash: "x"},
+			field: "id",
+		},
+		{
+			name:       "Unique field",
+			model:      &Gadget{Name: "Copy", Serial: "A1"},
+			field:      "serial",
+			constraint: "uni_gadgets_serial",
+		},
+	}
+
+	if err := accessor.Create(&Gadget{Name: "Widget", Serial: "A1"}); err != nil {
+		t.Fatalf("Failed to create gadget: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := accessor.Create(tt.model)
+			if !errors.Is(err, ErrUniqueViolation) {
+				t.Fatalf("Expected ErrUniqueViolation, got %v", err)
+			}
+
+			var integrityErr *IntegrityError
+			if !errors.As(err, &integrityErr) {
+				t.Fatalf("Expected an IntegrityError, got %T", err)
+			}
+			if integrityErr.Field != tt.field || integrityErr.Constraint != tt.constraint {
+				t.Errorf("Expected field %s and constraint %q, got %+v", tt.field, tt.constraint, integrityErr)
+			}
+		})
+	}
+
+	gadget := &Gadget{Name: "Other", Serial: "B2"}
+	if err := accessor.Create(gadget); err != nil {
+		t.Fatalf("Failed to create gadget: %v", err)
+	}
+	gadget.Serial = "A1"
+	if err := accessor.Update(gadget); !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("Expected ErrUniqueViolation on update, got %v", err)
+	}
+}
+
+// TestMemory_Transaction tests committing and rolling back transactions
+func TestMemory_Transaction(t *testing.T) {
+	accessor := setupMemoryArticles(t)
+
+	err := accessor.Transaction(func(tx *Accessor) error {
+		if err := tx.Create(&Article{Title: "Committed", Author: "dave"}); err != nil {
+			return err
+		}
+
+		// A failing nested transaction rolls back like a savepoint
+		errNested := errors.New("nested")
+		err := tx.Transaction(func(nested *Accessor) error {
+			if err := nested.Create(&Article{Title: "Rolled back", Author: "dave"}); err != nil {
+				return err
+			}
+			return errNested
+		})
+		if !errors.Is(err, errNested) {
+			t.Errorf("Expected nested error, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+
+	errRollback := errors.New("rollback")
+	err = accessor.Transaction(func(tx *Accessor) error {
+		article := &Article{}
+		if err := tx.Get(article, 1); err != nil {
+			return err
+		}
+		if err := tx.Delete(article); err != nil {
+			return err
+		}
+		if err := tx.Create(&Article{Title: "Discarded", Author: "dave"}); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("Expected rollback error, got %v", err)
+	}
+
+	var articles []Article
+	if err := accessor.Filter(&articles, map[string]interface{}{"author": "dave"}); err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if len(articles) != 1 || articles[0].Title != "Committed" {
+		t.Errorf("Expected only the committed article, got %+v", articles)
+	}
+	if err := accessor.Get(&Article{}, 1); err != nil {
+		t.Errorf("Expected the rolled back delete to be undone, got %v", err)
+	}
+
+	// Rolled back inserts free their IDs, like SQLite rowids
+	article := &Article{Title: "After", Author: "erin"}
+	if err := accessor.Create(article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	if article.ID != 6 {
+		t.Errorf("Expected ID 6, got %d", article.ID)
+	}
+}
+
+// TestMemory_TransactionConcurrency tests using the backend while a transaction runs
+func TestMemory_TransactionConcurrency(t *testing.T) {
+	accessor := setupMemoryArticles(t)
+	if err := accessor.Migrate(&User{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	// The outer Accessor and other goroutines do not wait for the transaction
+	err := accessor.Transaction(func(tx *Accessor) error {
+		if err := tx.Create(&Article{Title: "Inside", Author: "dave"}); err != nil {
+			return err
+		}
+		count, err := accessor.CountFilter(&Article{}, map[string]interface{}{"author": "dave"})
+		if err != nil {
+			return err
+		}
+		if count != 0 {
+			t.Errorf("Expected the uncommitted article to be invisible outside, got %d", count)
+		}
+
+		done := make(chan error)
+		go func() {
+			done <- accessor.Create(&User{Username: "outside", Email: "outside@example.com"})
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			return errors.New("write outside the transaction blocked")
+		}
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+
+	// Both the transaction's and the concurrent changes are kept
+	if count, err := accessor.CountFilter(&Article{}, map[string]interface{}{"author": "dave"}); err != nil || count != 1 {
+		t.Errorf("Expected the committed article, got %d (%v)", count, err)
+	}
+	if count, err := accessor.CountFilter(&User{}, map[string]interface{}{"username": "outside"}); err != nil || count != 1 {
+		t.Errorf("Expected the concurrent user, got %d (%v)", count, err)
+	}
+
+	// A table changed on both sides fails to commit rather than lose a change
+	err = accessor.Transaction(func(tx *Accessor) error {
+		if err := tx.Create(&Article{Title: "Inside", Author: "erin"}); err != nil {
+			return err
+		}
+		return accessor.Create(&Article{Title: "Outside", Author: "erin"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "transaction conflict: table articles") {
+		t.Errorf("Expected a transaction conflict, got %v", err)
+	}
+	var articles []Article
+	if err := accessor.Filter(&articles, map[string]interface{}{"author": "erin"}); err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if len(articles) != 1 || articles[0].Title != "Outside" {
+		t.Errorf("Expected only the article created outside, got %+v", articles)
+	}
+}
+
+// TestMemory_Migrate tests that tables must be migrated before use
+func TestMemory_Migrate(t *testing.T) {
+	accessor := NewAccessor(setupMemoryDB(t))
+
+	err := accessor.Create(&Article{Title: "Too early"})
+	if err == nil || !strings.Contains(err.Error(), "no such table: articles") {
+		t.Errorf("Expected a missing table error, got %v", err)
+	}
+
+	// Migrating twice keeps the records
+	for i := 0; i < 2; i++ {
+		if err := accessor.Migrate(&Article{}, &User{}); err != nil {
+			t.Fatalf("Failed to migrate: %v", err)
+		}
+		if i == 0 {
+			if err := accessor.Create(&Article{Title: "Kept"}); err != nil {
+				t.Fatalf("Failed to create article: %v", err)
+			}
+		}
+	}
+
+	tables, err := accessor.backend().Tables(accessor.Context())
+	if err != nil {
+		t.Fatalf("Failed to list tables: %v", err)
+	}
+	if strings.Join(tables, ",") != "articles,users" {
+		t.Errorf("Expected articles and users tables, got %v", tables)
+	}
+
+	count, err := accessor.CountFilter(&Article{}, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("CountFilter failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 article, got %d", count)
+	}
+}
+
+// TestLoadConfig_Memory tests selecting the memory backend with DB_TYPE
+func TestLoadConfig_Memory(t *testing.T) {
+	t.Setenv("DB_TYPE", memoryDBType)
+	t.Setenv("DB_NAME", "")
+
+	connection, err := InitDB()
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	if _, ok := connection.GetDB().(*memoryBackend); !ok {
+		t.Errorf("Expected the memory backend, got %T", connection.GetDB())
+	}
+}
@@ -0,0 +1,426 @@
+package gobase
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// memoryLike is the operator of LIKE conditions in raw memory queries. Its
+// value is the pattern compiled to a regular expression.
+const memoryLike = "like"
+
+// memoryWhereOperators maps the SQL operators supported in raw memory
+// queries to lookups. Negated operators are listed in memoryWhereNegated.
+var memoryWhereOperators = map[string]string{
+	"=":        LookupExact,
+	"==":       LookupExact,
+	"!=":       LookupExact,
+	"<>":       LookupExact,
+	">":        LookupGt,
+	">=":       LookupGte,
+	"<":        LookupLt,
+	"<=":       LookupLte,
+	"IN":       LookupIn,
+	"NOT IN":   LookupIn,
+	"LIKE":     memoryLike,
+	"NOT LIKE": memoryLike,
+}
+
+// memoryWhereNegated lists the negated operators of memoryWhereOperators.
+var memoryWhereNegated = map[string]bool{"!=": true, "<>": true, "NOT IN": true, "NOT LIKE": true}
+
+// memoryWhereTerm matches a comparison of a raw memory query, e.g.
+// "views >= ?", "author IN (?)" or "deleted_at IS NULL".
+var memoryWhereTerm = regexp.MustCompile(`(?i)^\s*["` + "`" + `]?(\w+)["` + "`" + `]?\s*` +
+	`(==|=|!=|<>|>=|>|<=|<|NOT\s+IN|IN|NOT\s+LIKE|LIKE|IS\s+NOT\s+NULL|IS\s+NULL)\s*(\?|\(\s*\?\s*\))?\s*$`)
+
+// memoryWhereAnd separates the comparisons of a raw memory query.
+var memoryWhereAnd = regexp.MustCompile(`(?i)\s+AND\s+`)
+
+// memoryCondition is a lookup compiled for the memory backend, which
+// matches records in Go.
+type memoryCondition struct {
+	field  *schema.Field
+	lookup lookup
+	value  interface{}
+	negate bool
+}
+
+// compileMemoryQuery compiles the conditions and raw condition of query.
+func compileMemoryQuery(s *schema.Schema, query Query) ([]memoryCondition, error) {
+	conditions, err := compileMemoryConditions(s, query.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	if query.Where == "" {
+		return conditions, nil
+	}
+
+	where, err := compileMemoryWhere(s, query.Where, query.Args)
+	if err != nil {
+		return nil, err
+	}
+	return append(conditions, where...), nil
+}
+
+// compileMemoryConditions validates a map of lookups for the memory
+// backend. Field names are resolved against the model schema, and keys are
+// sorted so errors are stable.
+func compileMemoryConditions(s *schema.Schema, conditions map[string]interface{}) ([]memoryCondition, error) {
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	compiled := make([]memoryCondition, 0, len(keys))
+	for _, key := range keys {
+		l, err := parseLookup(key)
+		if err != nil {
+			return nil, err
+		}
+
+		field := s.LookUpField(l.field)
+		if field == nil || field.DBName == "" {
+			return nil, &FieldError{Field: l.field, Model: s.Name}
+		}
+
+		value, err := l.memoryValue(conditions[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %w", key, err)
+		}
+		compiled = append(compiled, memoryCondition{field: field, lookup: l, value: value})
+	}
+	return compiled, nil
+}
+
+// compileMemoryWhere parses the raw condition of a memory query. The
+// memory backend supports comparisons of a column with a placeholder,
+// such as "views >= ?" or "author IN ?", IS NULL and IS NOT NULL, joined
+// with AND; other SQL returns ErrNotSupported.
+func compileMemoryWhere(s *schema.Schema, where string, args []interface{}) ([]memoryCondition, error) {
+	var compiled []memoryCondition
+	for _, term := range memoryWhereAnd.Split(strings.TrimSpace(where), -1) {
+		match := memoryWhereTerm.FindStringSubmatch(term)
+		if match == nil {
+			return nil, fmt.Errorf("%w: condition %q on the %s backend", ErrNotSupported, term, memoryDBType)
+		}
+
+		field := s.LookUpField(match[1])
+		if field == nil || field.DBName == "" {
+			return nil, &FieldError{Field: match[1], Model: s.Name}
+		}
+		operator := strings.ToUpper(strings.Join(strings.Fields(match[2]), " "))
+		condition := memoryCondition{field: field, lookup: lookup{field: match[1], operator: LookupIsNull}}
+
+		if strings.HasPrefix(operator, "IS ") {
+			if match[3] != "" {
+				return nil, fmt.Errorf("%w: condition %q on the %s backend", ErrNotSupported, term, memoryDBType)
+			}
+			condition.value = operator == "IS NULL"
+			compiled = append(compiled, condition)
+			continue
+		}
+
+		if match[3] == "" {
+			return nil, fmt.Errorf("%w: condition %q on the %s backend", ErrNotSupported, term, memoryDBType)
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("missing argument for condition %q", term)
+		}
+		condition.lookup.operator = memoryWhereOperators[operator]
+		condition.negate = memoryWhereNegated[operator]
+
+		value, err := condition.lookup.memoryValue(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %w", term, err)
+		}
+		condition.value = value
+		args = args[1:]
+		compiled = append(compiled, condition)
+	}
+
+	if len(args) > 0 {
+		return nil, fmt.Errorf("too many arguments for condition %q", where)
+	}
+	return compiled, nil
+}
+
+// memoryValue validates the value of the lookup and converts it to the
+// form compared with the records' values.
+func (l lookup) memoryValue(value interface{}) (interface{}, error) {
+	if l.transform == TransformDate {
+		value = dateValue(value)
+	}
+
+	switch l.operator {
+	case LookupExact, LookupGt, LookupGte, LookupLt, LookupLte:
+		return memoryValue(value), nil
+	case LookupIn, LookupRange:
+		var values []interface{}
+		var err error
+		if l.operator == LookupIn {
+			values, err = sliceValues(value)
+		} else {
+			values, err = rangeValues(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range values {
+			values[i] = memoryValue(values[i])
+		}
+		return values, nil
+	case LookupIsNull:
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("isnull requires a bool, got %T", value)
+		}
+		return value, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s requires a string, got %T", l.operator, value)
+	}
+	if l.operator == memoryLike {
+		return memoryLikePattern(s), nil
+	}
+	return s, nil
+}
+
+// memoryLikePattern compiles a LIKE pattern. Like SQLite's LIKE, it is
+// case-insensitive.
+func memoryLikePattern(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// memoryMatch reports whether row matches every condition.
+func memoryMatch(ctx context.Context, conditions []memoryCondition, row reflect.Value) bool {
+	for _, condition := range conditions {
+		if !condition.match(ctx, row) {
+			return false
+		}
+	}
+	return true
+}
+
+// match reports whether row matches the condition. As in SQL, negated
+// comparisons never match NULL.
+func (c memoryCondition) match(ctx context.Context, row reflect.Value) bool {
+	fieldValue, _ := c.field.ValueOf(ctx, row)
+	value := memoryTransform(c.lookup.transform, memoryValue(fieldValue))
+	matched := c.lookup.memoryMatch(value, c.value)
+	if c.negate {
+		return value != nil && !matched
+	}
+	return matched
+}
+
+// memoryMatch reports whether the lookup matches value, a record's value.
+func (l lookup) memoryMatch(value, target interface{}) bool {
+	switch {
+	case l.operator == LookupIsNull:
+		return (value == nil) == target.(bool)
+	case l.operator == LookupExact && target == nil:
+		return value == nil
+	case value == nil:
+		return false
+	}
+
+	switch l.operator {
+	case LookupExact:
+		return memoryEqual(value, target)
+	case LookupGt, LookupGte, LookupLt, LookupLte:
+		order, ok := memoryCompare(value, target)
+		switch {
+		case !ok:
+			return false
+		case l.operator == LookupGt:
+			return order > 0
+		case l.operator == LookupGte:
+			return order >= 0
+		case l.operator == LookupLt:
+			return order < 0
+		}
+		return order <= 0
+	case LookupIn:
+		for _, v := range target.([]interface{}) {
+			if memoryEqual(value, v) {
+				return true
+			}
+		}
+		return false
+	case LookupRange:
+		bounds := target.([]interface{})
+		low, lowOK := memoryCompare(value, bounds[0])
+		high, highOK := memoryCompare(value, bounds[1])
+		return lowOK && highOK && low >= 0 && high <= 0
+	case memoryLike:
+		return target.(*regexp.Regexp).MatchString(fmt.Sprint(value))
+	}
+
+	text, pattern := fmt.Sprint(value), target.(string)
+	if l.operator == LookupIExact {
+		return strings.EqualFold(text, pattern)
+	}
+	if strings.HasPrefix(l.operator, "i") {
+		text, pattern = strings.ToLower(text), strings.ToLower(pattern)
+	}
+	switch strings.TrimPrefix(l.operator, "i") {
+	case LookupContains:
+		return strings.Contains(text, pattern)
+	case LookupStartsWith:
+		return strings.HasPrefix(text, pattern)
+	}
+	return strings.HasSuffix(text, pattern)
+}
+
+// memoryTransform applies the date, year or month transform to a time.
+func memoryTransform(transform string, value interface{}) interface{} {
+	t, ok := value.(time.Time)
+	if transform == "" || !ok {
+		return value
+	}
+
+	switch transform {
+	case TransformDate:
+		return t.UTC().Format("2006-01-02")
+	case TransformYear:
+		return int64(t.UTC().Year())
+	}
+	return int64(t.UTC().Month())
+}
+
+// memoryValue returns the value stored for a field or condition value:
+// pointers are dereferenced, driver.Valuer types such as gorm.DeletedAt
+// are converted, and NULLs become nil.
+func memoryValue(value interface{}) interface{} {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+
+	value = rv.Interface()
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		return v
+	}
+	return value
+}
+
+// memoryEqual reports whether two stored values are equal.
+func memoryEqual(a, b interface{}) bool {
+	order, ok := memoryCompare(a, b)
+	return ok && order == 0
+}
+
+// memoryCompare orders two stored values, reporting false when they cannot
+// be compared. Numbers compare with numbers and numeric strings, as with
+// SQLite's column affinity, so Get(article, "3") finds the article with
+// ID 3.
+func memoryCompare(a, b interface{}) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	if at, ok := a.(time.Time); ok {
+		bt, ok := b.(time.Time)
+		return at.Compare(bt), ok
+	}
+	if ab, ok := a.([]byte); ok {
+		bb, ok := b.([]byte)
+		return bytes.Compare(ab, bb), ok
+	}
+
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case av.Kind() == reflect.String && bv.Kind() == reflect.String:
+		return strings.Compare(av.String(), bv.String()), true
+	case av.Kind() == reflect.Bool && bv.Kind() == reflect.Bool:
+		return memoryBool(av.Bool()) - memoryBool(bv.Bool()), true
+	case memoryIsInt(av) && memoryIsInt(bv):
+		return compareOrdered(memoryInt(av), memoryInt(bv)), true
+	}
+
+	af, aOK := memoryFloat(av)
+	bf, bOK := memoryFloat(bv)
+	return compareOrdered(af, bf), aOK && bOK
+}
+
+// compareOrdered returns -1, 0 or 1 as a is less than, equal to or greater
+// than b.
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// memoryBool converts a bool to 0 or 1, ordering false before true.
+func memoryBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// memoryIsInt reports whether rv holds an integer.
+func memoryIsInt(rv reflect.Value) bool {
+	return rv.Kind() >= reflect.Int && rv.Kind() <= reflect.Uint64
+}
+
+// memoryInt returns the integer held by rv.
+func memoryInt(rv reflect.Value) int64 {
+	if rv.Kind() >= reflect.Uint && rv.Kind() <= reflect.Uint64 {
+		return int64(rv.Uint())
+	}
+	return rv.Int()
+}
+
+// memoryFloat converts a number or numeric string to a float64.
+func memoryFloat(rv reflect.Value) (float64, bool) {
+	switch {
+	case memoryIsInt(rv):
+		return float64(memoryInt(rv)), true
+	case rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64:
+		return rv.Float(), true
+	case rv.Kind() == reflect.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
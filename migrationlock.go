@@ -0,0 +1,265 @@
+package gobase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultMigrationLockTimeout is how long Migrate and MigrateTo wait for
+// the migration lock held by another process unless configured otherwise
+// with WithMigrationLockTimeout.
+const DefaultMigrationLockTimeout = 5 * time.Minute
+
+// DefaultMigrationLockTTL is how old a SQLite migration lock must be before
+// it is considered abandoned and taken over, unless configured otherwise
+// with WithMigrationLockTTL.
+const DefaultMigrationLockTTL = time.Hour
+
+// migrationLockTable holds the migration lock on databases without
+// advisory locks. It contains a row while the lock is held.
+const migrationLockTable = "gobase_migration_lock"
+
+// migrationLockPollInterval is how often a waiting process retries the
+// migration lock.
+const migrationLockPollInterval = 100 * time.Millisecond
+
+// migrationLockKey is the PostgreSQL advisory lock key of the migration
+// lock.
+var migrationLockKey = func() int64 {
+	h := fnv.New64a()
+	h.Write([]byte(MigrationTableName))
+	return int64(h.Sum64())
+}()
+
+// WithMigrationLockTimeout returns a copy of the Accessor whose Migrate and
+// MigrateTo calls wait at most timeout for the migration lock before
+// failing with ErrMigrationLocked. The lock ensures that when several
+// replicas start at once, only one of them migrates while the others wait
+// and then find nothing left to do. A zero timeout fails immediately, for
+// replicas that should skip migrating instead:
+//
+//	err := accessor.WithMigrationLockTimeout(0).Migrate()
+//	if errors.Is(err, gobase.ErrMigrationLocked) {
+//		// another replica is migrating
+//	}
+//
+// PostgreSQL uses an advisory lock, released automatically if the process
+// dies. SQLite uses a row in the gobase_migration_lock table; if a process
+// crashes while migrating, its lock is taken over once older than the lock
+// TTL (see WithMigrationLockTTL), or can be cleared with UnlockMigrations.
+func (a *Accessor) WithMigrationLockTimeout(timeout time.Duration) *Accessor {
+	scoped := *a
+	scoped.lockTimeout = &timeout
+	return &scoped
+}
+
+// WithMigrationLockTTL returns a copy of the Accessor that takes over a
+// SQLite migration lock held for longer than ttl, assuming the process
+// holding it crashed. The TTL must exceed the longest migration, or a
+// slow migration may run concurrently with another. A zero TTL never
+// takes over a lock. PostgreSQL advisory locks do not need a TTL.
+func (a *Accessor) WithMigrationLockTTL(ttl time.Duration) *Accessor {
+	scoped := *a
+	scoped.lockTTL = &ttl
+	return &scoped
+}
+
+// UnlockMigrations forcibly releases a SQLite migration lock left behind
+// by a crashed process. It is a no-op on PostgreSQL, whose advisory locks
+// are released when their session ends.
+func (a *Accessor) UnlockMigrations() error {
+	// Only supported by Migrator backends
+	if err := a.requireMigrator("UnlockMigrations"); err != nil {
+		return err
+	}
+
+	if a.migrationDB().Dialector.Name() == dialectPostgres {
+		return nil
+	}
+	if err := a.ensureMigrationLockTable(); err != nil {
+		return err
+	}
+	return translateError(a.migrationDB().Exec("DELETE FROM " + migrationLockTable).Error)
+}
+
+// migrationLockTimeout returns how long to wait for the migration lock.
+func (a *Accessor) migrationLockTimeout() time.Duration {
+	if a.lockTimeout == nil {
+		return DefaultMigrationLockTimeout
+	}
+	return *a.lockTimeout
+}
+
+// migrationLockTTL returns the age after which a migration lock is stale.
+func (a *Accessor) migrationLockTTL() time.Duration {
+	if a.lockTTL == nil {
+		return DefaultMigrationLockTTL
+	}
+	return *a.lockTTL
+}
+
+// withMigrationLock runs fn while holding the migration lock, on
+// Migrator backends.
+func (a *Accessor) withMigrationLock(fn func() error) (err error) {
+	if _, ok := a.migrator(); !ok {
+		// The lock lives in the database; other backends migrate unlocked
+		return fn()
+	}
+
+	var unlock func() error
+	if a.migrationDB().Dialector.Name() == dialectPostgres {
+		unlock, err = a.lockMigrationsPostgres()
+	} else {
+		unlock, err = a.lockMigrationsTable()
+	}
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil && err == nil {
+			err = fmt.Errorf("failed to release migration lock: %w", unlockErr)
+		}
+	}()
+	return fn()
+}
+
+// lockMigrationsPostgres acquires the migration lock as a PostgreSQL
+// advisory lock. Outside of a transaction it is a session lock held on a
+// dedicated connection; inside one it is a transaction lock released when
+// the transaction ends.
+func (a *Accessor) lockMigrationsPostgres() (func() error, error) {
+	if _, inTransaction := a.migrationDB().Statement.ConnPool.(gorm.TxCommitter); inTransaction {
+		err := a.waitForMigrationLock(func() (bool, error) {
+			var locked bool
+			err := a.migrationDB().Raw("SELECT pg_try_advisory_xact_lock(?)", migrationLockKey).Scan(&locked).Error
+			return locked, err
+		})
+		return func() error { return nil }, err
+	}
+
+	sqlDB, err := a.migrationDB().DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(a.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.waitForMigrationLock(func() (bool, error) {
+		var locked bool
+		err := conn.QueryRowContext(a.Context(), "SELECT pg_try_advisory_lock($1)", migrationLockKey).Scan(&locked)
+		return locked, err
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() error {
+		// Unlock even if the Accessor's context has been cancelled
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+		if closeErr := conn.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
+
+// lockMigrationsTable acquires the migration lock by inserting the single
+// row of the migration lock table, taking over a lock older than the lock
+// TTL.
+func (a *Accessor) lockMigrationsTable() (func() error, error) {
+	if err := a.ensureMigrationLockTable(); err != nil {
+		return nil, err
+	}
+
+	insert := func() error {
+		return translateError(a.migrationDB().Exec(
+			"INSERT INTO "+migrationLockTable+" (id, locked_at) VALUES (1, ?)", a.migrationDB().NowFunc(),
+		).Error)
+	}
+	err := a.waitForMigrationLock(func() (bool, error) {
+		err := insert()
+		if errors.Is(err, ErrUniqueViolation) {
+			stale, staleErr := a.clearStaleMigrationLock()
+			if staleErr != nil || !stale {
+				return false, staleErr
+			}
+			// Another process may take over the stale lock first
+			err = insert()
+		}
+		if errors.Is(err, ErrUniqueViolation) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		// Unlock even if the Accessor's context has been cancelled
+		migrator, _ := a.migrator()
+		return translateError(migrator.MigrationDB(context.Background()).Exec("DELETE FROM " + migrationLockTable).Error)
+	}, nil
+}
+
+// clearStaleMigrationLock deletes the migration lock row if it is older
+// than the lock TTL and reports whether it did.
+func (a *Accessor) clearStaleMigrationLock() (bool, error) {
+	ttl := a.migrationLockTTL()
+	if ttl <= 0 {
+		return false, nil
+	}
+
+	result := a.migrationDB().Exec(
+		"DELETE FROM "+migrationLockTable+" WHERE locked_at < ?", a.migrationDB().NowFunc().Add(-ttl),
+	)
+	if result.Error != nil {
+		return false, translateError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ensureMigrationLockTable creates the migration lock table if needed.
+// Several processes may do so at once, hence IF NOT EXISTS.
+func (a *Accessor) ensureMigrationLockTable() error {
+	return translateError(a.migrationDB().Exec(
+		"CREATE TABLE IF NOT EXISTS " + migrationLockTable + " (id integer PRIMARY KEY, locked_at datetime NOT NULL)",
+	).Error)
+}
+
+// waitForMigrationLock calls try until it acquires the lock, the lock
+// timeout expires or the Accessor's context is done.
+func (a *Accessor) waitForMigrationLock(try func() (bool, error)) error {
+	deadline := time.Now().Add(a.migrationLockTimeout())
+	for {
+		locked, err := try()
+		if err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if locked {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return ErrMigrationLocked
+		}
+
+		timer := time.NewTimer(min(remaining, migrationLockPollInterval))
+		select {
+		case <-a.Context().Done():
+			timer.Stop()
+			return a.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package gobase
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// setupSharedDB opens n connections to the same SQLite file database, like
+// replicas of a deployment
+func setupSharedDB(t *testing.T, n int) []*Accessor {
+	name := filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000&_txlock=immediate"
+
+	var accessors []*Accessor
+	for i := 0; i < n; i++ {
+		connection, err := InitDBWithConfig(&DatabaseConfig{Type: "sqlite", Name: name})
+		if err != nil {
+			t.Fatalf("Failed to setup test database: %v", err)
+		}
+		t.Cleanup(func() { connection.Close() })
+		accessors = append(accessors, NewAccessor(connection))
+	}
+	return accessors
+}
+
+// TestAccessor_MigrationLock tests that a held lock blocks other migrations
+func TestAccessor_MigrationLock(t *testing.T) {
+	accessors := setupSharedDB(t, 2)
+	holder, waiter := accessors[0], accessors[1]
+
+	unlock, err := holder.lockMigrationsTable()
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+	}{
+		{name: "Skip when locked", timeout: 0},
+		{name: "Wait then give up", timeout: 250 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := waiter.WithMigrationLockTimeout(tt.timeout).Migrate(&Article{})
+			if !errors.Is(err, ErrMigrationLocked) {
+				t.Fatalf("Expected ErrMigrationLocked, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.timeout {
+				t.Errorf("Expected to wait %v, gave up after %v", tt.timeout, elapsed)
+			}
+
+			_, err = waiter.WithMigrationLockTimeout(tt.timeout).MigrateTo(noteMigrations(), MigrateOptions{})
+			if !errors.Is(err, ErrMigrationLocked) {
+				t.Errorf("Expected ErrMigrationLocked from MigrateTo, got %v", err)
+			}
+		})
+	}
+
+	// A waiting migration proceeds once the lock is released
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		unlock()
+	}()
+	if err := waiter.WithMigrationLockTimeout(5 * time.Second).Migrate(&Article{}); err != nil {
+		t.Fatalf("Expected migration to proceed after unlock, got %v", err)
+	}
+
+	// The lock is released after migrating
+	if err := holder.WithMigrationLockTimeout(0).Migrate(&Article{}); err != nil {
+		t.Errorf("Expected lock to be released, got %v", err)
+	}
+}
+
+// TestAccessor_MigrationLockConcurrent tests that concurrent replicas apply each migration once
+func TestAccessor_MigrationLockConcurrent(t *testing.T) {
+	accessors := setupSharedDB(t, 4)
+
+	migrations := append(noteMigrations(), Migration{
+		Name: "0004_seed",
+		Up:   []string{"INSERT INTO notes (body) VALUES ('seed')"},
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(accessors))
+	applied := make(chan int, len(accessors))
+	for _, accessor := range accessors {
+		wg.Add(1)
+		go func(accessor *Accessor) {
+			defer wg.Done()
+			steps, err := accessor.MigrateTo(migrations, MigrateOptions{})
+			if err != nil {
+				errs <- err
+				return
+			}
+			applied <- len(steps)
+		}(accessor)
+	}
+	wg.Wait()
+	close(errs)
+	close(applied)
+
+	for err := range errs {
+		t.Errorf("MigrateTo failed: %v", err)
+	}
+	total := 0
+	for n := range applied {
+		total += n
+	}
+	if total != len(migrations) {
+		t.Errorf("Expected %d migrations applied in total, got %d", len(migrations), total)
+	}
+
+	var count int64
+	if err := accessors[0].connection.GormDB.Table("notes").Count(&count).Error; err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected the seed to run once, got %d rows", count)
+	}
+}
+
+// TestAccessor_UnlockMigrations tests clearing a stale lock
+func TestAccessor_UnlockMigrations(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+
+	// Simulate a process that crashed while holding the lock
+	if _, err := accessor.lockMigrationsTable(); err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+	if err := accessor.WithMigrationLockTimeout(0).Migrate(&Article{}); !errors.Is(err, ErrMigrationLocked) {
+		t.Fatalf("Expected ErrMigrationLocked, got %v", err)
+	}
+
+	if err := accessor.UnlockMigrations(); err != nil {
+		t.Fatalf("UnlockMigrations failed: %v", err)
+	}
+	if err := accessor.WithMigrationLockTimeout(0).Migrate(&Article{}); err != nil {
+		t.Errorf("Expected migration to succeed, got %v", err)
+	}
+}
+
+// TestAccessor_MigrationLockTTL tests taking over a lock left by a crashed process
+func TestAccessor_MigrationLockTTL(t *testing.T) {
+	accessors := setupSharedDB(t, 2)
+	holder, waiter := accessors[0], accessors[1]
+
+	// Simulate a process that crashed while holding the lock two hours ago
+	if _, err := holder.lockMigrationsTable(); err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+	lockedAt := time.Now().Add(-2 * time.Hour)
+	if err := holder.connection.GormDB.Exec("UPDATE "+migrationLockTable+" SET locked_at = ?", lockedAt).Error; err != nil {
+		t.Fatalf("Failed to age lock: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantErr error
+	}{
+		{name: "Lock within TTL", ttl: 3 * time.Hour, wantErr: ErrMigrationLocked},
+		{name: "Zero TTL", ttl: 0, wantErr: ErrMigrationLocked},
+		{name: "Stale lock", ttl: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := waiter.WithMigrationLockTimeout(0).WithMigrationLockTTL(tt.ttl).Migrate(&Article{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// The lock taken over is released after migrating
+	if err := holder.WithMigrationLockTimeout(0).Migrate(&Article{}); err != nil {
+		t.Errorf("Expected lock to be released, got %v", err)
+	}
+}
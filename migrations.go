@@ -0,0 +1,509 @@
+package gobase
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MigrationTableName is the table recording which migrations have been
+// applied to a database.
+const MigrationTableName = "gobase_migrations"
+
+// Section markers of SQL migration files.
+const (
+	migrationUpMarker   = "-- +gobase Up"
+	migrationDownMarker = "-- +gobase Down"
+)
+
+// migrationName matches migration names such as "0001_initial". The
+// numeric prefix orders migrations.
+var migrationName = regexp.MustCompile(`^(\d+)_[A-Za-z0-9_]+$`)
+
+// Migration is a versioned schema change, like a Django migration. Up holds
+// the SQL statements applying the change and Down those reverting it.
+// Migrations are generated by MakeMigrations as SQL files (loaded with
+// LoadMigrations) or Go files (which call RegisterMigration), and applied
+// in name order by ApplyMigrations.
+//
+// Migrations registered in Go may also change data, like Django's
+// RunPython. Forward runs after the Up statements and Reverse before the
+// Down statements, in the same transaction; the Accessor they receive is
+// bound to it:
+//
+//	gobase.RegisterMigration(gobase.Migration{
+//		Name: "0003_split_names",
+//		Forward: func(ctx context.Context, accessor *gobase.Accessor) error {
+//			// backfill FirstName and LastName from FullName
+//		},
+//		Reverse: gobase.MigrationNoop,
+//	})
+type Migration struct {
+	Name    string
+	Up      []string
+	Down    []string
+	Forward MigrationFunc
+	Reverse MigrationFunc
+}
+
+// MigrationFunc is a data migration step written in Go.
+type MigrationFunc func(ctx context.Context, accessor *Accessor) error
+
+// MigrationNoop is a MigrationFunc doing nothing, used as the Reverse of
+// data migrations that need no undoing so that they can be unapplied.
+func MigrationNoop(context.Context, *Accessor) error {
+	return nil
+}
+
+// appliedMigration is a row of the migrations table.
+type appliedMigration struct {
+	ID        uint      `gorm:"primarykey"`
+	Name      string    `gorm:"size:255;not null;uniqueIndex"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName implements gorm's tabler interface.
+func (appliedMigration) TableName() string {
+	return MigrationTableName
+}
+
+// migrationRegistry holds the migrations registered by generated Go
+// migration files.
+var migrationRegistry []Migration
+
+// RegisterMigration registers a migration defined in Go, typically from the
+// init function of a file generated by MakeMigrations. Registered
+// migrations are returned by LoadMigrations along with SQL files.
+func RegisterMigration(migration Migration) {
+	migrationRegistry = append(migrationRegistry, migration)
+}
+
+// RegisteredMigrations returns the migrations registered with
+// RegisterMigration.
+func RegisteredMigrations() []Migration {
+	return migrationRegistry
+}
+
+// LoadMigrations returns the migrations defined by the .sql files at the
+// root of fsys together with those registered in Go, sorted by name. fsys
+// may be nil to only use registered migrations.
+//
+// A SQL migration file is named after its migration, e.g.
+// "0002_add_article_views.sql", and holds an Up and a Down section. Each
+// statement ends with a semicolon at the end of a line:
+//
+//	-- +gobase Up
+//	ALTER TABLE "articles" ADD COLUMN "views" bigint;
+//
+//	-- +gobase Down
+//	ALTER TABLE "articles" DROP COLUMN "views";
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	migrations := append([]Migration(nil), migrationRegistry...)
+
+	if fsys != nil {
+		names, err := fs.Glob(fsys, "*.sql")
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			file, err := fsys.Open(name)
+			if err != nil {
+				return nil, err
+			}
+			migration, err := parseSQLMigration(strings.TrimSuffix(path.Base(name), ".sql"), file)
+			file.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse migration %s: %w", name, err)
+			}
+			migrations = append(migrations, migration)
+		}
+	}
+
+	if err := sortMigrations(migrations); err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
+
+// parseSQLMigration reads a migration from a SQL migration file.
+func parseSQLMigration(name string, r io.Reader) (Migration, error) {
+	migration := Migration{Name: name}
+
+	var (
+		section   *[]string
+		statement strings.Builder
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch trimmed {
+		case migrationUpMarker, migrationDownMarker:
+			if strings.TrimSpace(statement.String()) != "" {
+				return migration, errors.New("statement is missing a terminating semicolon")
+			}
+			statement.Reset()
+			section = &migration.Up
+			if trimmed == migrationDownMarker {
+				section = &migration.Down
+			}
+			continue
+		}
+
+		if section == nil {
+			if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				return migration, fmt.Errorf("statement outside of an %q or %q section", migrationUpMarker, migrationDownMarker)
+			}
+			continue
+		}
+		if statement.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			*section = append(*section, strings.TrimSuffix(strings.TrimSpace(statement.String()), ";"))
+			statement.Reset()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return migration, err
+	}
+	if strings.TrimSpace(statement.String()) != "" {
+		return migration, errors.New("statement is missing a terminating semicolon")
+	}
+
+	return migration, nil
+}
+
+// sortMigrations validates migration names and sorts migrations by their
+// numeric prefix.
+func sortMigrations(migrations []Migration) error {
+	seen := make(map[string]bool, len(migrations))
+	for _, migration := range migrations {
+		if !migrationName.MatchString(migration.Name) {
+			return fmt.Errorf("invalid migration name %q: expected a numbered name such as 0001_initial", migration.Name)
+		}
+		if seen[migration.Name] {
+			return fmt.Errorf("duplicate migration %q", migration.Name)
+		}
+		seen[migration.Name] = true
+	}
+
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrationLess(migrations[i].Name, migrations[j].Name)
+	})
+	return nil
+}
+
+// migrationLess orders migration names by numeric prefix, then by name.
+func migrationLess(a, b string) bool {
+	na, nb := migrationNumber(a), migrationNumber(b)
+	if na != nb {
+		return na < nb
+	}
+	return a < b
+}
+
+// migrationNumber returns the numeric prefix of a migration name, or -1.
+func migrationNumber(name string) int {
+	match := migrationName.FindStringSubmatch(name)
+	if match == nil {
+		return -1
+	}
+	number, err := strconv.Atoi(match[1])
+	if err != nil {
+		return -1
+	}
+	return number
+}
+
+// AppliedMigrations returns the names of the migrations applied to the
+// database, in the order they were applied.
+func (a *Accessor) AppliedMigrations() ([]string, error) {
+	// Only supported by Migrator backends
+	if err := a.requireMigrator("AppliedMigrations"); err != nil {
+		return nil, err
+	}
+
+	if err := a.ensureMigrationTable(); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	err := a.migrationDB().Model(&appliedMigration{}).Order("id").Pluck("name", &names).Error
+	return names, translateError(err)
+}
+
+// ApplyMigrations applies the migrations that have not been applied yet,
+// in name order, and returns the names of those applied. Each migration
+// runs in its own transaction together with its record in the
+// gobase_migrations table, so a failing migration leaves no trace.
+//
+//	migrations, err := gobase.LoadMigrations(os.DirFS("migrations"))
+//	applied, err := accessor.ApplyMigrations(migrations)
+func (a *Accessor) ApplyMigrations(migrations []Migration) ([]string, error) {
+	steps, err := a.MigrateTo(migrations, MigrateOptions{})
+	var names []string
+	for _, step := range steps {
+		names = append(names, step.Migration.Name)
+	}
+	return names, err
+}
+
+// MigrateZero is the MigrateOptions target unapplying every migration.
+const MigrateZero = "zero"
+
+// MigrateOptions configures MigrateTo.
+type MigrateOptions struct {
+	// Target is the migration to migrate to. Pending migrations up to and
+	// including an unapplied target are applied, while the migrations
+	// applied after an applied target are unapplied. Empty targets the
+	// latest migration, and MigrateZero unapplies all migrations.
+	Target string
+	// Fake records the migrations as applied or unapplied without running
+	// their SQL, e.g. to adopt migrations for an existing schema.
+	Fake bool
+}
+
+// MigrationStep is a migration applied or unapplied by MigrateTo.
+type MigrationStep struct {
+	Migration Migration
+	Backwards bool
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// ShowMigrations returns the status of each migration, in name order, like
+// Django's showmigrations.
+func (a *Accessor) ShowMigrations(migrations []Migration) ([]MigrationStatus, error) {
+	migrations, applied, err := a.migrationHistory(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Name: migration.Name}
+		if record, ok := applied[migration.Name]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// MigrationPlan returns the steps MigrateTo would run, without running
+// them.
+func (a *Accessor) MigrationPlan(migrations []Migration, target string) ([]MigrationStep, error) {
+	migrations, applied, err := a.migrationHistory(migrations)
+	if err != nil {
+		return nil, err
+	}
+	return planMigrations(migrations, applied, target)
+}
+
+// MigrateTo migrates the database forwards or backwards to
+// options.Target and returns the steps run, like Django's migrate command.
+// Each step runs in its own transaction together with its record in the
+// gobase_migrations table. Unapplying a migration runs its Reverse function
+// and Down statements, and fails for migrations without either. The
+// migration lock is held
+// throughout, so concurrent callers wait and then find nothing to do.
+//
+//	// Revert everything after 0002_add_article_views
+//	steps, err := accessor.MigrateTo(migrations, gobase.MigrateOptions{Target: "0002_add_article_views"})
+func (a *Accessor) MigrateTo(migrations []Migration, options MigrateOptions) ([]MigrationStep, error) {
+	// Only supported by Migrator backends
+	if err := a.requireMigrator("MigrateTo"); err != nil {
+		return nil, err
+	}
+
+	var steps []MigrationStep
+	err := a.withMigrationLock(func() (err error) {
+		steps, err = a.migrateTo(migrations, options)
+		return err
+	})
+	return steps, err
+}
+
+// migrateTo runs MigrateTo's plan; the caller holds the migration lock.
+func (a *Accessor) migrateTo(migrations []Migration, options MigrateOptions) ([]MigrationStep, error) {
+	plan, err := a.MigrationPlan(migrations, options.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []MigrationStep
+	for _, step := range plan {
+		if err := a.runMigrationStep(step, options.Fake); err != nil {
+			action := "apply"
+			if step.Backwards {
+				action = "unapply"
+			}
+			return steps, fmt.Errorf("failed to %s migration %s: %w", action, step.Migration.Name, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// SQL returns the statements of the migration as a SQL script, or of its
+// reversal when backwards is true, like Django's sqlmigrate. Go functions
+// are shown as a comment.
+func (m Migration) SQL(backwards bool) string {
+	statements, fn := m.Up, m.Forward
+	if backwards {
+		statements, fn = m.Down, m.Reverse
+	}
+
+	var script strings.Builder
+	if fn != nil && backwards {
+		script.WriteString("-- Go data migration function (not shown)\n")
+	}
+	for _, statement := range statements {
+		script.WriteString(statement)
+		script.WriteString(";\n")
+	}
+	if fn != nil && !backwards {
+		script.WriteString("-- Go data migration function (not shown)\n")
+	}
+	return script.String()
+}
+
+// migrationHistory sorts migrations and returns them with the applied
+// migration records by name.
+func (a *Accessor) migrationHistory(migrations []Migration) ([]Migration, map[string]appliedMigration, error) {
+	// Only supported by Migrator backends
+	if err := a.requireMigrator("Migrations"); err != nil {
+		return nil, nil, err
+	}
+
+	migrations = append([]Migration(nil), migrations...)
+	if err := sortMigrations(migrations); err != nil {
+		return nil, nil, err
+	}
+
+	if err := a.ensureMigrationTable(); err != nil {
+		return nil, nil, err
+	}
+	var records []appliedMigration
+	if err := a.migrationDB().Order("id").Find(&records).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	applied := make(map[string]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Name] = record
+	}
+	return migrations, applied, nil
+}
+
+// planMigrations returns the steps migrating to target.
+func planMigrations(migrations []Migration, applied map[string]appliedMigration, target string) ([]MigrationStep, error) {
+	known := make(map[string]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Name] = true
+	}
+
+	// Position of the last migration to keep applied
+	last := len(migrations) - 1
+	switch target {
+	case "":
+	case MigrateZero:
+		last = -1
+	default:
+		last = -2
+		for i, migration := range migrations {
+			if migration.Name == target {
+				last = i
+			}
+		}
+		if last == -2 {
+			return nil, fmt.Errorf("unknown migration %q", target)
+		}
+	}
+
+	var steps []MigrationStep
+	if _, ok := applied[target]; target == MigrateZero || ok {
+		for name := range applied {
+			if !known[name] {
+				return nil, fmt.Errorf("applied migration %q is not among the loaded migrations", name)
+			}
+		}
+		for i := len(migrations) - 1; i > last; i-- {
+			if _, ok := applied[migrations[i].Name]; ok {
+				steps = append(steps, MigrationStep{Migration: migrations[i], Backwards: true})
+			}
+		}
+		return steps, nil
+	}
+
+	for _, migration := range migrations[:last+1] {
+		if _, ok := applied[migration.Name]; !ok {
+			steps = append(steps, MigrationStep{Migration: migration})
+		}
+	}
+	return steps, nil
+}
+
+// runMigrationStep applies or unapplies a migration in a transaction and
+// records the result. Fake steps only record it.
+func (a *Accessor) runMigrationStep(step MigrationStep, fake bool) error {
+	migration := step.Migration
+	statements := migration.Up
+	if step.Backwards {
+		statements = migration.Down
+		if len(statements) == 0 && migration.Reverse == nil && !fake {
+			return errors.New("migration is irreversible: it has no Down statements or Reverse function")
+		}
+	}
+
+	return a.Transaction(func(tx *Accessor) error {
+		if !fake {
+			if step.Backwards && migration.Reverse != nil {
+				if err := migration.Reverse(tx.Context(), tx); err != nil {
+					return err
+				}
+			}
+			for _, statement := range statements {
+				if err := tx.migrationDB().Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			if !step.Backwards && migration.Forward != nil {
+				if err := migration.Forward(tx.Context(), tx); err != nil {
+					return err
+				}
+			}
+		}
+
+		if step.Backwards {
+			return tx.migrationDB().Where("name = ?", step.Migration.Name).Delete(&appliedMigration{}).Error
+		}
+		return tx.migrationDB().Create(&appliedMigration{Name: step.Migration.Name, AppliedAt: tx.migrationDB().NowFunc()}).Error
+	})
+}
+
+// ensureMigrationTable creates the gobase_migrations table if needed.
+func (a *Accessor) ensureMigrationTable() error {
+	return a.migrationDB().AutoMigrate(&appliedMigration{})
+}
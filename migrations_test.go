@@ -0,0 +1,524 @@
+package gobase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestParseSQLMigration tests reading SQL migration files
+func TestParseSQLMigration(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		expectedUp   []string
+		expectedDown []string
+		expectError  bool
+	}{
+		{
+			name: "Up and Down sections",
+			content: `-- Migration 0001_initial
+-- +gobase Up
+CREATE TABLE notes (id integer);
+-- a comment
+CREATE INDEX idx_notes_id ON notes(id);
+
+-- +gobase Down
+DROP TABLE notes;
+`,
+			expectedUp:   []string{"CREATE TABLE notes (id integer)", "CREATE INDEX idx_notes_id ON notes(id)"},
+			expectedDown: []string{"DROP TABLE notes"},
+		},
+		{
+			name: "Multi-line statement",
+			content: `-- +gobase Up
+UPDATE notes
+SET id = 1;
+`,
+			expectedUp: []string{"UPDATE notes\nSET id = 1"},
+		},
+		{
+			name:        "Statement outside of a section",
+			content:     "DROP TABLE notes;\n",
+			expectError: true,
+		},
+		{
+			name:        "Missing semicolon",
+			content:     "-- +gobase Up\nDROP TABLE notes\n-- +gobase Down\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := parseSQLMigration("0001_initial", strings.NewReader(tt.content))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(migration.Up, tt.expectedUp) {
+				t.Errorf("Expected Up %q, got %q", tt.expectedUp, migration.Up)
+			}
+			if !reflect.DeepEqual(migration.Down, tt.expectedDown) {
+				t.Errorf("Expected Down %q, got %q", tt.expectedDown, migration.Down)
+			}
+		})
+	}
+}
+
+// TestLoadMigrations tests combining SQL files with registered migrations
+func TestLoadMigrations(t *testing.T) {
+	saved := migrationRegistry
+	defer func() { migrationRegistry = saved }()
+	migrationRegistry = nil
+
+	RegisterMigration(Migration{Name: "0002_seed", Up: []string{"INSERT INTO notes (id) VALUES (1)"}})
+
+	fsys := fstest.MapFS{
+		"0010_later.sql":   {Data: []byte("-- +gobase Up\nSELECT 1;\n")},
+		"0001_initial.sql": {Data: []byte("-- +gobase Up\nCREATE TABLE notes (id integer);\n-- +gobase Down\nDROP TABLE notes;\n")},
+		"README.md":        {Data: []byte("not a migration")},
+	}
+
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("LoadMigrations failed: %v", err)
+	}
+
+	var names []string
+	for _, migration := range migrations {
+		names = append(names, migration.Name)
+	}
+	expected := []string{"0001_initial", "0002_seed", "0010_later"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	// Duplicate and badly named migrations are rejected
+	RegisterMigration(Migration{Name: "0001_initial"})
+	if _, err := LoadMigrations(fsys); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("Expected duplicate migration error, got %v", err)
+	}
+	if _, err := LoadMigrations(fstest.MapFS{"initial.sql": {Data: []byte("")}}); err == nil {
+		t.Error("Expected error for unnumbered migration")
+	}
+}
+
+// TestAccessor_ApplyMigrations tests applying and recording migrations
+func TestAccessor_ApplyMigrations(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+
+	migrations := []Migration{
+		{Name: "0002_seed", Up: []string{"INSERT INTO notes (body) VALUES ('hello')"}},
+		{Name: "0001_initial", Up: []string{"CREATE TABLE notes (id integer PRIMARY KEY, body text)"}, Down: []string{"DROP TABLE notes"}},
+	}
+
+	applied, err := accessor.ApplyMigrations(migrations)
+	if err != nil {
+		t.Fatalf("ApplyMigrations failed: %v", err)
+	}
+	if !reflect.DeepEqual(applied, []string{"0001_initial", "0002_seed"}) {
+		t.Errorf("Expected migrations to be applied in order, got %v", applied)
+	}
+
+	// Applied migrations are skipped
+	applied, err = accessor.ApplyMigrations(migrations)
+	if err != nil {
+		t.Fatalf("ApplyMigrations failed: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("Expected nothing to apply, got %v", applied)
+	}
+
+	var count int64
+	if err := accessor.connection.GormDB.Table("notes").Count(&count).Error; err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected seed to run once, got %d rows", count)
+	}
+
+	// A failing migration is rolled back and not recorded
+	migrations = append(migrations, Migration{Name: "0003_broken", Up: []string{
+		"INSERT INTO notes (body) VALUES ('partial')",
+		"INSERT INTO missing_table (id) VALUES (1)",
+	}})
+	if _, err := accessor.ApplyMigrations(migrations); err == nil || !strings.Contains(err.Error(), "0003_broken") {
+		t.Errorf("Expected error naming the failed migration, got %v", err)
+	}
+
+	names, err := accessor.AppliedMigrations()
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"0001_initial", "0002_seed"}) {
+		t.Errorf("Expected failed migration not to be recorded, got %v", names)
+	}
+	if err := accessor.connection.GormDB.Table("notes").Count(&count).Error; err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected failed migration to be rolled back, got %d rows", count)
+	}
+}
+
+// noteMigrations returns migrations creating a notes table step by step
+func noteMigrations() []Migration {
+	return []Migration{
+		{Name: "0001_initial", Up: []string{"CREATE TABLE notes (id integer PRIMARY KEY)"}, Down: []string{"DROP TABLE notes"}},
+		{Name: "0002_body", Up: []string{"ALTER TABLE notes ADD COLUMN body text"}, Down: []string{"ALTER TABLE notes DROP COLUMN body"}},
+		{Name: "0003_title", Up: []string{"ALTER TABLE notes ADD COLUMN title text"}, Down: []string{"ALTER TABLE notes DROP COLUMN title"}},
+	}
+}
+
+// TestAccessor_MigrateTo tests migrating forwards and backwards to a target
+func TestAccessor_MigrateTo(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	migrations := noteMigrations()
+
+	tests := []struct {
+		name            string
+		options         MigrateOptions
+		expectedSteps   []string
+		expectedApplied []string
+	}{
+		{
+			name:            "Forwards to target",
+			options:         MigrateOptions{Target: "0002_body"},
+			expectedSteps:   []string{"+0001_initial", "+0002_body"},
+			expectedApplied: []string{"0001_initial", "0002_body"},
+		},
+		{
+			name:            "Forwards to latest",
+			options:         MigrateOptions{},
+			expectedSteps:   []string{"+0003_title"},
+			expectedApplied: []string{"0001_initial", "0002_body", "0003_title"},
+		},
+		{
+			name:            "Backwards to target",
+			options:         MigrateOptions{Target: "0001_initial"},
+			expectedSteps:   []string{"-0003_title", "-0002_body"},
+			expectedApplied: []string{"0001_initial"},
+		},
+		{
+			name:            "Already at target",
+			options:         MigrateOptions{Target: "0001_initial"},
+			expectedApplied: []string{"0001_initial"},
+		},
+		{
+			name:          "Backwards to zero",
+			options:       MigrateOptions{Target: MigrateZero},
+			expectedSteps: []string{"-0001_initial"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := accessor.MigrateTo(migrations, tt.options)
+			if err != nil {
+				t.Fatalf("MigrateTo failed: %v", err)
+			}
+
+			var names []string
+			for _, step := range steps {
+				prefix := "+"
+				if step.Backwards {
+					prefix = "-"
+				}
+				names = append(names, prefix+step.Migration.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expectedSteps) {
+				t.Errorf("Expected steps %v, got %v", tt.expectedSteps, names)
+			}
+
+			applied, err := accessor.AppliedMigrations()
+			if err != nil {
+				t.Fatalf("AppliedMigrations failed: %v", err)
+			}
+			if strings.Join(applied, ",") != strings.Join(tt.expectedApplied, ",") {
+				t.Errorf("Expected applied %v, got %v", tt.expectedApplied, applied)
+			}
+		})
+	}
+
+	if accessor.connection.GormDB.Migrator().HasTable("notes") {
+		t.Error("Expected notes table to be dropped")
+	}
+}
+
+// TestAccessor_MigrateToFake tests recording migrations without running them
+func TestAccessor_MigrateToFake(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	migrations := noteMigrations()
+
+	// The schema already exists, e.g. created before migrations were adopted
+	if err := accessor.connection.GormDB.Exec("CREATE TABLE notes (id integer PRIMARY KEY, body text)").Error; err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	if _, err := accessor.MigrateTo(migrations, MigrateOptions{Target: "0002_body", Fake: true}); err != nil {
+		t.Fatalf("MigrateTo failed: %v", err)
+	}
+	if _, err := accessor.MigrateTo(migrations, MigrateOptions{}); err != nil {
+		t.Fatalf("MigrateTo failed: %v", err)
+	}
+	if !accessor.connection.GormDB.Migrator().HasColumn("notes", "title") {
+		t.Error("Expected 0003_title to run")
+	}
+
+	statuses, err := accessor.ShowMigrations(migrations)
+	if err != nil {
+		t.Fatalf("ShowMigrations failed: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.AppliedAt.IsZero() {
+			t.Errorf("Expected %s to be applied, got %+v", status.Name, status)
+		}
+	}
+
+	if _, err := accessor.MigrateTo(migrations, MigrateOptions{Target: MigrateZero, Fake: true}); err != nil {
+		t.Fatalf("MigrateTo failed: %v", err)
+	}
+	if !accessor.connection.GormDB.Migrator().HasTable("notes") {
+		t.Error("Expected fake unapply to keep the table")
+	}
+}
+
+// TestAccessor_MigrateToErrors tests invalid targets and irreversible migrations
+func TestAccessor_MigrateToErrors(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	migrations := append(noteMigrations(), Migration{Name: "0004_seed", Up: []string{"INSERT INTO notes (id) VALUES (1)"}})
+
+	if _, err := accessor.MigrateTo(migrations, MigrateOptions{Target: "0009_missing"}); err == nil {
+		t.Error("Expected error for unknown target")
+	}
+
+	if _, err := accessor.MigrateTo(migrations, MigrateOptions{}); err != nil {
+		t.Fatalf("MigrateTo failed: %v", err)
+	}
+
+	// Reverting stops at the irreversible migration
+	if _, err := accessor.MigrateTo(migrations, MigrateOptions{Target: "0003_title"}); err == nil || !strings.Contains(err.Error(), "irreversible") {
+		t.Errorf("Expected irreversible migration error, got %v", err)
+	}
+
+	// Applied migrations must be known to migrate backwards
+	if _, err := accessor.MigrateTo(migrations[:3], MigrateOptions{Target: MigrateZero}); err == nil || !strings.Contains(err.Error(), "0004_seed") {
+		t.Errorf("Expected unknown applied migration error, got %v", err)
+	}
+}
+
+// TestMigration_SQL tests rendering migrations as SQL scripts
+func TestMigration_SQL(t *testing.T) {
+	migration := noteMigrations()[1]
+
+	if sql := migration.SQL(false); sql != "ALTER TABLE notes ADD COLUMN body text;\n" {
+		t.Errorf("Unexpected forwards SQL %q", sql)
+	}
+	if sql := migration.SQL(true); sql != "ALTER TABLE notes DROP COLUMN body;\n" {
+		t.Errorf("Unexpected backwards SQL %q", sql)
+	}
+
+	// Go functions are noted in the script
+	if sql := splitContactNames().SQL(false); !strings.Contains(sql, "Go data migration") {
+		t.Errorf("Expected Go function comment, got %q", sql)
+	}
+}
+
+// Contact is a model whose names are split by a data migration
+type Contact struct {
+	BaseModel
+	FullName  string
+	FirstName string
+	LastName  string
+}
+
+// splitContactNames is the data migration backfilling FirstName and LastName
+func splitContactNames() Migration {
+	return Migration{
+		Name: "0001_split_names",
+		Forward: func(ctx context.Context, accessor *Accessor) error {
+			var contacts []Contact
+			if err := accessor.All(&contacts); err != nil {
+				return err
+			}
+			for i := range contacts {
+				contacts[i].FirstName, contacts[i].LastName, _ = strings.Cut(contacts[i].FullName, " ")
+			}
+			_, err := accessor.BulkUpdate(&contacts, "FirstName", "LastName")
+			return err
+		},
+		Reverse: func(ctx context.Context, accessor *Accessor) error {
+			_, err := accessor.Objects(&Contact{}).Update(map[string]interface{}{"first_name": "", "last_name": ""})
+			return err
+		},
+	}
+}
+
+// TestAccessor_DataMigrations tests running Go functions forwards and backwards
+func TestAccessor_DataMigrations(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&Contact{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if err := accessor.Create(&Contact{FullName: "Ada Lovelace"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	migrations := []Migration{splitContactNames()}
+
+	if _, err := accessor.MigrateTo(migrations, MigrateOptions{}); err != nil {
+		t.Fatalf("MigrateTo failed: %v", err)
+	}
+	var contact Contact
+	if err := accessor.Get(&contact, 1); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if contact.FirstName != "Ada" || contact.LastName != "Lovelace" {
+		t.Errorf("Expected names to be split, got %q %q", contact.FirstName, contact.LastName)
+	}
+
+	if _, err := accessor.MigrateTo(migrations, MigrateOptions{Target: MigrateZero}); err != nil {
+		t.Fatalf("MigrateTo zero failed: %v", err)
+	}
+	contact = Contact{}
+	if err := accessor.Get(&contact, 1); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if contact.FirstName != "" || contact.LastName != "" {
+		t.Errorf("Expected names to be cleared, got %q %q", contact.FirstName, contact.LastName)
+	}
+
+	// A noop Reverse makes a data migration reversible
+	noop := Migration{Name: "0001_noop", Forward: MigrationNoop, Reverse: MigrationNoop}
+	if _, err := accessor.MigrateTo([]Migration{noop}, MigrateOptions{}); err != nil {
+		t.Fatalf("MigrateTo failed: %v", err)
+	}
+	if _, err := accessor.MigrateTo([]Migration{noop}, MigrateOptions{Target: MigrateZero}); err != nil {
+		t.Errorf("Expected noop migration to be reversible, got %v", err)
+	}
+}
+
+// TestAccessor_DataMigrationRollback tests that a failing function rolls back its migration
+func TestAccessor_DataMigrationRollback(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	migrations := []Migration{{
+		Name: "0001_initial",
+		Up:   []string{"CREATE TABLE notes (id integer PRIMARY KEY)"},
+		Forward: func(ctx context.Context, accessor *Accessor) error {
+			return errors.New("backfill failed")
+		},
+	}}
+
+	if _, err := accessor.MigrateTo(migrations, MigrateOptions{}); err == nil || !strings.Contains(err.Error(), "backfill failed") {
+		t.Fatalf("Expected backfill error, got %v", err)
+	}
+	if accessor.connection.GormDB.Migrator().HasTable("notes") {
+		t.Error("Expected the Up statements to be rolled back")
+	}
+	applied, err := accessor.AppliedMigrations()
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("Expected no applied migrations, got %v", applied)
+	}
+}
+
+// TestAccessor_MigrateRunsRegisteredMigrations tests that Migrate applies registered migrations
+func TestAccessor_MigrateRunsRegisteredMigrations(t *testing.T) {
+	saved := migrationRegistry
+	defer func() { migrationRegistry = saved }()
+	migrationRegistry = nil
+	RegisterMigration(splitContactNames())
+
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&Contact{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if err := accessor.Create(&Contact{FullName: "Alan Turing"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// The migration ran on the empty table and is not run again
+	if err := accessor.Migrate(&Contact{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	var contact Contact
+	if err := accessor.Get(&contact, 1); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if contact.FirstName != "" {
+		t.Errorf("Expected the migration to run once, got FirstName %q", contact.FirstName)
+	}
+
+	applied, err := accessor.AppliedMigrations()
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %v", err)
+	}
+	if !reflect.DeepEqual(applied, []string{"0001_split_names"}) {
+		t.Errorf("Expected 0001_split_names to be applied, got %v", applied)
+	}
+}
+
+// TestAccessor_MigrateStopsAtSchemaMigrations tests that Migrate does not run data migrations past a pending schema migration
+func TestAccessor_MigrateStopsAtSchemaMigrations(t *testing.T) {
+	saved := migrationRegistry
+	defer func() { migrationRegistry = saved }()
+	migrationRegistry = nil
+
+	accessor := NewAccessor(setupTestDB(t))
+	dir := t.TempDir()
+	if _, err := accessor.MakeMigrations(dir, MakeMigrationsOptions{}, &Contact{}); err != nil {
+		t.Fatalf("MakeMigrations failed: %v", err)
+	}
+	generated, err := LoadMigrations(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("LoadMigrations failed: %v", err)
+	}
+
+	// Register them as a generated Go migration file does
+	for _, migration := range generated {
+		RegisterMigration(migration)
+	}
+	backfill := splitContactNames()
+	backfill.Name = "0002_split_names"
+	RegisterMigration(backfill)
+
+	// The backfill must not run before the pending schema migration
+	err = accessor.Migrate(&Contact{})
+	if !errors.Is(err, ErrSchemaMigrationPending) || !strings.Contains(err.Error(), generated[0].Name) {
+		t.Fatalf("Expected ErrSchemaMigrationPending for %s, got %v", generated[0].Name, err)
+	}
+	applied, err := accessor.AppliedMigrations()
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("Expected no migrations to be applied, got %v", applied)
+	}
+
+	// Once the schema migration is recorded, Migrate applies the backfill
+	_, err = accessor.MigrateTo(RegisteredMigrations(), MigrateOptions{Target: generated[0].Name, Fake: true})
+	if err != nil {
+		t.Fatalf("MigrateTo failed: %v", err)
+	}
+	if err := accessor.Migrate(&Contact{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	applied, err = accessor.AppliedMigrations()
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %v", err)
+	}
+	if !reflect.DeepEqual(applied, []string{generated[0].Name, "0002_split_names"}) {
+		t.Errorf("Expected the migrations to be applied in order, got %v", applied)
+	}
+}
@@ -4,7 +4,7 @@
 // coming from Python/Django while maintaining Go's type safety and performance characteristics.
 //
 // Key Features:
-//   - Multi-database support (SQLite, PostgreSQL, MongoDB planned)
+//   - Multi-database support (SQLite, PostgreSQL, MongoDB)
 //   - Django-inspired model patterns with BaseModel
 //   - Automatic migrations and schema management
 //   - Built-in user management with authentication
@@ -0,0 +1,505 @@
+package gobase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm/schema"
+)
+
+// MongoCountersCollection holds the sequences from which documents with
+// integer IDs, such as those embedding BaseModel, get their ID on Create,
+// like auto-increment columns. It has a document per collection.
+const MongoCountersCollection = "gobase_counters"
+
+// mongoIDKey is the key of a document's primary key.
+const mongoIDKey = "_id"
+
+// mongoSchemaCache caches the schemas of models stored in MongoDB.
+var mongoSchemaCache sync.Map
+
+// mongoNamer names collections and keys like GORM names tables and
+// columns, so the same models work with both kinds of databases.
+var mongoNamer = schema.NamingStrategy{IdentifierMaxLength: 64}
+
+// mongoDBType is the database type of MongoDB connections.
+const mongoDBType = "mongodb"
+
+// mongoBackend is the Backend for MongoDB. Documents are stored in the
+// collection named like the model's table, with a key per column and the
+// primary key as _id.
+type mongoBackend struct {
+	client   *mongo.Client
+	database *mongo.Database
+
+	// session is the session of the transaction the backend is bound to,
+	// if any.
+	session mongo.Session
+}
+
+// context binds ctx to the backend's transaction session, if any.
+func (b *mongoBackend) context(ctx context.Context) context.Context {
+	if b.session == nil {
+		return ctx
+	}
+	return mongo.NewSessionContext(ctx, b.session)
+}
+
+// collection returns the collection storing the model's documents.
+func (b *mongoBackend) collection(s *schema.Schema) *mongo.Collection {
+	return b.database.Collection(s.Table)
+}
+
+// mongoSchema parses the schema of a model stored in MongoDB. Documents
+// are stored in the collection named like the model's table, with a key
+// per column and the primary key as _id.
+func mongoSchema(model interface{}) (*schema.Schema, error) {
+	s, err := schema.Parse(model, &mongoSchemaCache, mongoNamer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model schema: %w", err)
+	}
+	if s.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("model '%s' has no primary key", s.Name)
+	}
+	return s, nil
+}
+
+// mongoModel returns the schema and addressable struct value of model,
+// which must be a pointer.
+func mongoModel(model interface{}) (*schema.Schema, reflect.Value, error) {
+	rv := reflect.ValueOf(model)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, reflect.Value{}, errors.New("model must be a non-nil pointer")
+	}
+	s, err := mongoSchema(model)
+	if err != nil {
+		return nil, reflect.Value{}, err
+	}
+	return s, rv.Elem(), nil
+}
+
+// mongoKey returns the document key of a field.
+func mongoKey(s *schema.Schema, field *schema.Field) string {
+	if field == s.PrioritizedPrimaryField {
+		return mongoIDKey
+	}
+	return field.DBName
+}
+
+// mongoDocument converts a model to a BSON document.
+func mongoDocument(ctx context.Context, s *schema.Schema, rv reflect.Value) (bson.D, error) {
+	document := make(bson.D, 0, len(s.DBNames))
+	for _, name := range s.DBNames {
+		field := s.FieldsByDBName[name]
+		value, _ := field.ValueOf(ctx, rv)
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode field %s: %w", field.Name, err)
+			}
+			value = v
+		}
+		document = append(document, bson.E{Key: mongoKey(s, field), Value: value})
+	}
+	return document, nil
+}
+
+// decodeMongoDocument sets the fields of a model from a BSON document.
+func decodeMongoDocument(ctx context.Context, s *schema.Schema, document bson.M, rv reflect.Value) error {
+	for _, name := range s.DBNames {
+		field := s.FieldsByDBName[name]
+		value, ok := document[mongoKey(s, field)]
+		if !ok {
+			continue
+		}
+		if dateTime, isDateTime := value.(primitive.DateTime); isDateTime {
+			value = dateTime.Time()
+		}
+		if err := field.Set(ctx, rv, value); err != nil {
+			return fmt.Errorf("failed to decode field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// mongoIDValue converts id to the type of the model's primary key as
+// stored, so that e.g. Get(article, "3") finds the article with ID 3.
+func mongoIDValue(s *schema.Schema, id interface{}) (interface{}, error) {
+	fieldType := s.PrioritizedPrimaryField.IndirectFieldType
+	if fieldType == reflect.TypeOf(primitive.ObjectID{}) {
+		if hex, ok := id.(string); ok {
+			return primitive.ObjectIDFromHex(hex)
+		}
+		return id, nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value := reflect.ValueOf(id)
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return value.Int(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return int64(value.Uint()), nil
+		case reflect.String:
+			n, err := strconv.ParseInt(value.String(), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid id %q: %w", value.String(), err)
+			}
+			return n, nil
+		}
+	case reflect.String:
+		return fmt.Sprint(id), nil
+	}
+	return id, nil
+}
+
+// mongoNow returns the current time at the millisecond precision of BSON
+// dates, so that models hold the timestamps read back later.
+func mongoNow() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
+
+// assignID gives a new model an ID unless it has one: the next value of
+// the collection's sequence for integer IDs, or a new ObjectID for
+// ObjectID and string IDs.
+func (b *mongoBackend) assignID(ctx context.Context, s *schema.Schema, rv reflect.Value) error {
+	field := s.PrioritizedPrimaryField
+	if _, zero := field.ValueOf(ctx, rv); !zero {
+		return nil
+	}
+
+	fieldType := field.IndirectFieldType
+	switch {
+	case fieldType == reflect.TypeOf(primitive.ObjectID{}):
+		return field.Set(ctx, rv, primitive.NewObjectID())
+	case fieldType.Kind() == reflect.String:
+		return field.Set(ctx, rv, primitive.NewObjectID().Hex())
+	case fieldType.Kind() >= reflect.Int && fieldType.Kind() <= reflect.Uint64:
+		var counter struct {
+			Seq int64 `bson:"seq"`
+		}
+		err := b.database.Collection(MongoCountersCollection).FindOneAndUpdate(ctx,
+			bson.M{mongoIDKey: s.Table},
+			bson.M{"$inc": bson.M{"seq": 1}},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&counter)
+		if err != nil {
+			return fmt.Errorf("failed to generate id: %w", translateError(err))
+		}
+		return field.Set(ctx, rv, counter.Seq)
+	}
+	return fmt.Errorf("unsupported primary key type %s for MongoDB", fieldType)
+}
+
+// mongoDeletedFilter restricts filter to the documents of the soft-delete
+// scope.
+func mongoDeletedFilter(s *schema.Schema, filter bson.D, scope DeletedScope) bson.D {
+	field := deletedAtField(s)
+	if field == nil || scope == IncludeDeleted {
+		if field == nil && scope == OnlyDeleted {
+			return bson.D{{Key: mongoIDKey, Value: bson.M{"$exists": false}}}
+		}
+		return filter
+	}
+
+	// Missing and null keys both mean live documents
+	condition := bson.E{Key: field.DBName, Value: nil}
+	if scope == OnlyDeleted {
+		condition.Value = bson.M{"$ne": nil}
+	}
+	if len(filter) == 0 {
+		return bson.D{condition}
+	}
+	return bson.D{{Key: "$and", Value: bson.A{filter, bson.D{condition}}}}
+}
+
+// mongoCondition parses the raw condition of a Query, a query document in
+// MongoDB Extended JSON, e.g. `{"views": {"$gte": 10}}`.
+func mongoCondition(condition string, args []interface{}) (bson.D, error) {
+	if len(args) > 0 {
+		return nil, errors.New("MongoDB conditions are query documents and take no arguments")
+	}
+	if condition == "" {
+		return bson.D{}, nil
+	}
+
+	var filter bson.D
+	if err := bson.UnmarshalExtJSON([]byte(condition), false, &filter); err != nil {
+		return nil, fmt.Errorf("invalid MongoDB condition: %w", err)
+	}
+	return filter, nil
+}
+
+// mongoFilter compiles a Query on the model with schema s into a MongoDB
+// query document, within the query's soft-delete scope.
+func mongoFilter(s *schema.Schema, query Query) (bson.D, error) {
+	filter, err := mongoCondition(query.Where, query.Args)
+	if err != nil {
+		return nil, err
+	}
+	conditions, err := compileMongoConditions(s, query.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	return mongoDeletedFilter(s, mongoAnd(filter, conditions), query.Deleted), nil
+}
+
+// mongoAnd combines query documents with $and, leaving out empty ones.
+func mongoAnd(filters ...bson.D) bson.D {
+	operands := bson.A{}
+	for _, filter := range filters {
+		if len(filter) > 0 {
+			operands = append(operands, filter)
+		}
+	}
+
+	switch len(operands) {
+	case 0:
+		return bson.D{}
+	case 1:
+		return operands[0].(bson.D)
+	}
+	return bson.D{{Key: "$and", Value: operands}}
+}
+
+// Create implements Backend.
+func (b *mongoBackend) Create(ctx context.Context, model interface{}) error {
+	ctx = b.context(ctx)
+	s, rv, err := mongoModel(model)
+	if err != nil {
+		return err
+	}
+
+	now := mongoNow()
+	for _, field := range s.Fields {
+		if field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
+			if _, zero := field.ValueOf(ctx, rv); zero {
+				timeType := max(field.AutoCreateTime, field.AutoUpdateTime)
+				if err := field.Set(ctx, rv, autoTimestamp(field, now, timeType)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := b.assignID(ctx, s, rv); err != nil {
+		return err
+	}
+
+	document, err := mongoDocument(ctx, s, rv)
+	if err != nil {
+		return err
+	}
+	_, err = b.collection(s).InsertOne(ctx, document)
+	return translateError(err)
+}
+
+// Get implements Backend.
+func (b *mongoBackend) Get(ctx context.Context, model interface{}, id interface{}, query Query) error {
+	ctx = b.context(ctx)
+	s, rv, err := mongoModel(model)
+	if err != nil {
+		return err
+	}
+	idValue, err := mongoIDValue(s, id)
+	if err != nil {
+		return err
+	}
+
+	filter, err := mongoFilter(s, query)
+	if err != nil {
+		return err
+	}
+	filter = mongoAnd(bson.D{{Key: mongoIDKey, Value: idValue}}, filter)
+
+	var document bson.M
+	if err := b.collection(s).FindOne(ctx, filter).Decode(&document); err != nil {
+		return translateError(err)
+	}
+	return decodeMongoDocument(ctx, s, document, rv)
+}
+
+// Find implements Backend. Documents are returned in _id order.
+func (b *mongoBackend) Find(ctx context.Context, models interface{}, query Query) error {
+	ctx = b.context(ctx)
+	rv := reflect.ValueOf(models)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("models must be a pointer to a slice")
+	}
+	s, err := mongoSchema(models)
+	if err != nil {
+		return err
+	}
+	filter, err := mongoFilter(s, query)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := b.collection(s).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: mongoIDKey, Value: 1}}))
+	if err != nil {
+		return translateError(err)
+	}
+	defer cursor.Close(ctx)
+
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	records := reflect.MakeSlice(slice.Type(), 0, 0)
+	for cursor.Next(ctx) {
+		var document bson.M
+		if err := cursor.Decode(&document); err != nil {
+			return translateError(err)
+		}
+
+		record := reflect.New(structType)
+		if err := decodeMongoDocument(ctx, s, document, record.Elem()); err != nil {
+			return err
+		}
+		if elemType.Kind() == reflect.Ptr {
+			records = reflect.Append(records, record)
+		} else {
+			records = reflect.Append(records, record.Elem())
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return translateError(err)
+	}
+
+	slice.Set(records)
+	return nil
+}
+
+// Count implements Backend.
+func (b *mongoBackend) Count(ctx context.Context, model interface{}, query Query) (int64, error) {
+	ctx = b.context(ctx)
+	s, err := mongoSchema(model)
+	if err != nil {
+		return 0, err
+	}
+	filter, err := mongoFilter(s, query)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := b.collection(s).CountDocuments(ctx, filter)
+	return count, translateError(err)
+}
+
+// Update implements Backend. Like GORM's Save, it writes every field, and
+// creates the document when the model has no ID yet or its document does
+// not exist.
+func (b *mongoBackend) Update(ctx context.Context, model interface{}) error {
+	s, rv, err := mongoModel(model)
+	if err != nil {
+		return err
+	}
+	if _, zero := s.PrioritizedPrimaryField.ValueOf(ctx, rv); zero {
+		return b.Create(ctx, model)
+	}
+	ctx = b.context(ctx)
+
+	now := mongoNow()
+	for _, field := range s.Fields {
+		if field.AutoUpdateTime > 0 {
+			if err := field.Set(ctx, rv, autoTimestamp(field, now, field.AutoUpdateTime)); err != nil {
+				return err
+			}
+		}
+	}
+
+	document, err := mongoDocument(ctx, s, rv)
+	if err != nil {
+		return err
+	}
+	id, _ := s.PrioritizedPrimaryField.ValueOf(ctx, rv)
+	_, err = b.collection(s).ReplaceOne(ctx, bson.D{{Key: mongoIDKey, Value: id}}, document,
+		options.Replace().SetUpsert(true))
+	return translateError(err)
+}
+
+// Delete implements Backend: it sets deleted_at, or removes the document
+// of models without soft deletion.
+func (b *mongoBackend) Delete(ctx context.Context, model interface{}) error {
+	ctx = b.context(ctx)
+	s, rv, err := mongoModel(model)
+	if err != nil {
+		return err
+	}
+	relations, err := onDeleteRelations(s)
+	if err != nil {
+		return err
+	}
+	if len(relations) > 0 {
+		return fmt.Errorf("%w: on_delete actions on the %s backend", ErrNotSupported, mongoDBType)
+	}
+
+	id, _ := s.PrioritizedPrimaryField.ValueOf(ctx, rv)
+	filter := bson.D{{Key: mongoIDKey, Value: id}}
+	field := deletedAtField(s)
+	if field == nil {
+		_, err := b.collection(s).DeleteOne(ctx, filter)
+		return translateError(err)
+	}
+
+	now := mongoNow()
+	filter = append(filter, bson.E{Key: field.DBName, Value: nil})
+	_, err = b.collection(s).UpdateOne(ctx, filter, bson.M{"$set": bson.M{field.DBName: now}})
+	if err != nil {
+		return translateError(err)
+	}
+	return field.Set(ctx, rv, now)
+}
+
+// Transaction implements Backend. The session's WithTransaction commits
+// when fn succeeds and aborts otherwise, retrying fn on
+// TransientTransactionError and the commit on
+// UnknownTransactionCommitResult. Nested transactions join the outer one.
+func (b *mongoBackend) Transaction(ctx context.Context, fn func(tx Backend) error) error {
+	if b.session != nil {
+		return fn(b)
+	}
+	if b.client == nil {
+		return errors.New("MongoDB transactions require a client connection")
+	}
+
+	session, err := b.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", translateError(err))
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(mongo.SessionContext) (interface{}, error) {
+		return nil, fn(&mongoBackend{client: b.client, database: b.database, session: session})
+	})
+	return translateError(err)
+}
+
+// Tables implements Backend, returning the collections.
+func (b *mongoBackend) Tables(ctx context.Context) ([]string, error) {
+	names, err := b.database.ListCollectionNames(b.context(ctx), bson.D{})
+	return names, translateError(err)
+}
+
+// Close implements Backend.
+func (b *mongoBackend) Close() error {
+	if b.client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return b.client.Disconnect(ctx)
+}
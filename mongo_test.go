@@ -0,0 +1,636 @@
+package gobase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+)
+
+// setupMongoDB connects to the mongod at MONGODB_URI, failing the test if
+// it cannot. Without MONGODB_URI it tries the local mongod at
+// MONGODB_HOST:MONGODB_PORT, skipping the test when none is running. Each
+// test gets its own database, dropped on cleanup.
+func setupMongoDB(t *testing.T) *Connection {
+	host, port := mongoTestAddress(t)
+
+	if os.Getenv("MONGODB_URI") == "" {
+		address := net.JoinHostPort(host, strconv.Itoa(port))
+		probe, err := net.DialTimeout("tcp", address, time.Second)
+		if err != nil {
+			t.Skipf("MongoDB is not available at %s: %v", address, err)
+		}
+		probe.Close()
+	}
+
+	config := &DatabaseConfig{
+		Type: mongoDBType,
+		Host: host,
+		Port: port,
+		Name: fmt.Sprintf("gobase_test_%d", time.Now().UnixNano()),
+	}
+	connection, err := InitDBWithConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+	t.Cleanup(func() {
+		connection.MongoDB.Drop(context.Background())
+		connection.Close()
+	})
+
+	return connection
+}
+
+// mongoTestAddress returns the host and port of the test mongod, from
+// MONGODB_URI if set and otherwise from MONGODB_HOST and MONGODB_PORT.
+func mongoTestAddress(t *testing.T) (string, int) {
+	if uri := os.Getenv("MONGODB_URI"); uri != "" {
+		parsed, err := url.Parse(uri)
+		if err != nil || parsed.Scheme != "mongodb" || parsed.Hostname() == "" {
+			t.Fatalf("Invalid MONGODB_URI %q: expected mongodb://host:port", uri)
+		}
+		port := 27017
+		if value := parsed.Port(); value != "" {
+			if port, err = strconv.Atoi(value); err != nil {
+				t.Fatalf("Invalid MONGODB_URI port: %v", err)
+			}
+		}
+		return parsed.Hostname(), port
+	}
+
+	host := os.Getenv("MONGODB_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+	port := 27017
+	if value := os.Getenv("MONGODB_PORT"); value != "" {
+		var err error
+		if port, err = strconv.Atoi(value); err != nil {
+			t.Fatalf("Invalid MONGODB_PORT: %v", err)
+		}
+	}
+	return host, port
+}
+
+// setupMongoArticles creates a MongoDB test database with a few articles
+func setupMongoArticles(t *testing.T) *Accessor {
+	return createMongoArticles(t, NewAccessor(setupMongoDB(t)))
+}
+
+// createMongoArticles creates a few articles
+func createMongoArticles(t *testing.T, accessor *Accessor) *Accessor {
+	articles := []*Article{
+		{Title: "Go Basics", Author: "alice", Status: "published", Views: 10},
+		{Title: "Advanced Go", Author: "bob", Status: "published", Views: 50},
+		{Title: "Draft Notes", Author: "alice", Status: "draft", Views: 0},
+		{Title: "Django Tips", Author: "carol", Status: "published", Views: 30},
+	}
+
+	for _, article := range articles {
+		if err := accessor.Create(article); err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+
+	return accessor
+}
+
+// TestMongo_Create tests that documents get sequential IDs and timestamps
+func TestMongo_Create(t *testing.T) {
+	accessor := setupMongoArticles(t)
+
+	article := &Article{Title: "New", Author: "dave"}
+	if err := accessor.Create(article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	if article.ID != 5 {
+		t.Errorf("Expected ID 5, got %d", article.ID)
+	}
+	if article.CreatedAt.IsZero() || article.UpdatedAt.IsZero() {
+		t.Error("Expected CreatedAt and UpdatedAt to be set")
+	}
+}
+
+// TestMongo_Get tests retrieving documents by ID
+func TestMongo_Get(t *testing.T) {
+	accessor := setupMongoArticles(t)
+
+	tests := []struct {
+		name        string
+		id          interface{}
+		expectError error
+		expected    string
+	}{
+		{name: "Integer ID", id: 2, expected: "Advanced Go"},
+		{name: "Unsigned ID", id: uint(4), expected: "Django Tips"},
+		{name: "String ID", id: "1", expected: "Go Basics"},
+		{name: "Missing document", id: 99, expectError: ErrDoesNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var article Article
+			err := accessor.Get(&article, tt.id)
+			if tt.expectError != nil {
+				if !errors.Is(err, tt.expectError) {
+					t.Fatalf("Expected %v, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to get article: %v", err)
+			}
+			if article.Title != tt.expected {
+				t.Errorf("Expected title %s, got %s", tt.expected, article.Title)
+			}
+			if article.CreatedAt.IsZero() {
+				t.Error("Expected CreatedAt to be decoded")
+			}
+		})
+	}
+}
+
+// TestMongo_All tests retrieving all documents in ID order
+func TestMongo_All(t *testing.T) {
+	accessor := setupMongoArticles(t)
+
+	var articles []Article
+	if err := accessor.All(&articles); err != nil {
+		t.Fatalf("Failed to get all articles: %v", err)
+	}
+	if len(articles) != 4 {
+		t.Fatalf("Expected 4 articles, got %d", len(articles))
+	}
+	for i, article := range articles {
+		if article.ID != uint(i+1) {
+			t.Errorf("Expected ID %d at position %d, got %d", i+1, i, article.ID)
+		}
+	}
+
+	var pointers []*Article
+	if err := accessor.All(&pointers); err != nil {
+		t.Fatalf("Failed to get all articles: %v", err)
+	}
+	if len(pointers) != 4 || pointers[0].Title != "Go Basics" {
+		t.Errorf("Expected 4 articles starting with Go Basics, got %v", pointers)
+	}
+}
+
+// TestMongo_Update tests replacing a document
+func TestMongo_Update(t *testing.T) {
+	accessor := setupMongoArticles(t)
+
+	var article Article
+	if err := accessor.Get(&article, 3); err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	createdAt := article.CreatedAt
+
+	article.Status = "published"
+	article.Views = 5
+	if err := accessor.Update(&article); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+
+	var updated Article
+	if err := accessor.Get(&updated, 3); err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	if updated.Status != "published" || updated.Views != 5 {
+		t.Errorf("Expected published article with 5 views, got %s with %d", updated.Status, updated.Views)
+	}
+	if !updated.CreatedAt.Equal(createdAt) {
+		t.Errorf("Expected CreatedAt %v to be kept, got %v", createdAt, updated.CreatedAt)
+	}
+
+	count, err := accessor.Count(&Article{}, "")
+	if err != nil {
+		t.Fatalf("Failed to count articles: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("Expected 4 articles after update, got %d", count)
+	}
+}
+
+// TestMongo_Delete tests that Delete soft-deletes documents
+func TestMongo_Delete(t *testing.T) {
+	accessor := setupMongoArticles(t)
+
+	article := &Article{}
+	if err := accessor.Get(article, 2); err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	if err := accessor.Delete(article); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	if !article.IsDeleted() {
+		t.Error("Expected article to be marked deleted")
+	}
+
+	if err := accessor.Get(&Article{}, 2); !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Expected ErrDoesNotExist for deleted article, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		accessor *Accessor
+		expected int64
+	}{
+		{name: "Default scope", accessor: accessor, expected: 3},
+		{name: "With deleted", accessor: accessor.WithDeleted(), expected: 4},
+		{name: "Only deleted", accessor: accessor.OnlyDeleted(), expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := tt.accessor.Count(&Article{}, "")
+			if err != nil {
+				t.Fatalf("Failed to count articles: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("Expected %d articles, got %d", tt.expected, count)
+			}
+		})
+	}
+
+	var deleted Article
+	if err := accessor.WithDeleted().Get(&deleted, 2); err != nil {
+		t.Fatalf("Failed to get deleted article: %v", err)
+	}
+	if !deleted.IsDeleted() {
+		t.Error("Expected decoded article to be marked deleted")
+	}
+}
+
+// TestMongo_DeleteOnDelete tests that on_delete actions are reported as unsupported
+func TestMongo_DeleteOnDelete(t *testing.T) {
+	accessor := NewAccessor(setupMongoDB(t))
+
+	publisher := &Publisher{Name: "Acme"}
+	if err := accessor.Create(publisher); err != nil {
+		t.Fatalf("Failed to create publisher: %v", err)
+	}
+	if err := accessor.Delete(publisher); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+}
+
+// TestMongo_FindWhere tests Extended JSON conditions
+func TestMongo_FindWhere(t *testing.T) {
+	accessor := setupMongoArticles(t)
+
+	tests := []struct {
+		name        string
+		condition   string
+		args        []interface{}
+		expected    int
+		expectError bool
+	}{
+		{name: "Equality", condition: `{"author": "alice"}`, expected: 2},
+		{name: "Operator", condition: `{"views": {"$gte": 30}}`, expected: 2},
+		{name: "Empty condition", condition: "", expected: 4},
+		{name: "Invalid JSON", condition: `{"views": `, expectError: true},
+		{name: "Arguments", condition: `{"author": ?}`, args: []interface{}{"alice"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			err := accessor.FindWhere(&articles, tt.condition, tt.args...)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to find articles: %v", err)
+			}
+			if len(articles) != tt.expected {
+				t.Errorf("Expected %d articles, got %d", tt.expected, len(articles))
+			}
+		})
+	}
+
+	count, err := accessor.Count(&Article{}, `{"status": "published"}`)
+	if err != nil {
+		t.Fatalf("Failed to count articles: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 published articles, got %d", count)
+	}
+}
+
+// TestMongo_UniqueViolation tests that duplicate keys are translated
+func TestMongo_UniqueViolation(t *testing.T) {
+	accessor := setupMongoArticles(t)
+
+	err := accessor.Create(&Article{BaseModel: BaseModel{ID: 1}, Title: "Duplicate"})
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Fatalf("Expected ErrUniqueViolation, got %v", err)
+	}
+
+	var integrityErr *IntegrityError
+	if errors.As(err, &integrityErr) && integrityErr.Field != mongoIDKey {
+		t.Errorf("Expected field %s, got %s", mongoIDKey, integrityErr.Field)
+	}
+}
+
+// TestMongoDocument tests converting models to and from BSON documents
+func TestMongoDocument(t *testing.T) {
+	ctx := context.Background()
+	now := mongoNow()
+	article := Article{
+		BaseModel: BaseModel{ID: 7, CreatedAt: now, UpdatedAt: now, DeletedAt: gorm.DeletedAt{Time: now, Valid: true}},
+		Title:     "Go Basics",
+		Views:     10,
+	}
+
+	s, err := mongoSchema(&article)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	if s.Table != "articles" {
+		t.Errorf("Expected collection articles, got %s", s.Table)
+	}
+
+	document, err := mongoDocument(ctx, s, reflect.ValueOf(&article).Elem())
+	if err != nil {
+		t.Fatalf("Failed to encode article: %v", err)
+	}
+	encoded, err := bson.Marshal(document)
+	if err != nil {
+		t.Fatalf("Failed to marshal document: %v", err)
+	}
+
+	var decodedDocument bson.M
+	if err := bson.Unmarshal(encoded, &decodedDocument); err != nil {
+		t.Fatalf("Failed to unmarshal document: %v", err)
+	}
+	if _, ok := decodedDocument[mongoIDKey]; !ok {
+		t.Errorf("Expected the ID to be stored as %s, got %v", mongoIDKey, decodedDocument)
+	}
+	if _, ok := decodedDocument["id"]; ok {
+		t.Error("Expected no id key")
+	}
+
+	var decoded Article
+	if err := decodeMongoDocument(ctx, s, decodedDocument, reflect.ValueOf(&decoded).Elem()); err != nil {
+		t.Fatalf("Failed to decode article: %v", err)
+	}
+	if decoded.ID != 7 || decoded.Title != "Go Basics" || decoded.Views != 10 {
+		t.Errorf("Expected decoded article to match, got %+v", decoded)
+	}
+	if !decoded.CreatedAt.Equal(now) || !decoded.DeletedAt.Valid || !decoded.DeletedAt.Time.Equal(now) {
+		t.Errorf("Expected decoded timestamps %v, got %v and %v", now, decoded.CreatedAt, decoded.DeletedAt)
+	}
+}
+
+// TestMongoIDValue tests converting IDs to the stored primary key type
+func TestMongoIDValue(t *testing.T) {
+	type ObjectIDModel struct {
+		ID   primitive.ObjectID `gorm:"primarykey"`
+		Name string
+	}
+	objectID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		id          interface{}
+		expected    interface{}
+		expectError bool
+	}{
+		{name: "Integer", model: &Article{}, id: 3, expected: int64(3)},
+		{name: "Unsigned", model: &Article{}, id: uint(3), expected: int64(3)},
+		{name: "Numeric string", model: &Article{}, id: "3", expected: int64(3)},
+		{name: "Invalid string", model: &Article{}, id: "abc", expectError: true},
+		{name: "String key", model: &CustomIDModel{}, id: "abc", expected: "abc"},
+		{name: "ObjectID hex", model: &ObjectIDModel{}, id: objectID.Hex(), expected: objectID},
+		{name: "ObjectID", model: &ObjectIDModel{}, id: objectID, expected: objectID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := mongoSchema(tt.model)
+			if err != nil {
+				t.Fatalf("Failed to parse schema: %v", err)
+			}
+
+			value, err := mongoIDValue(s, tt.id)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if value != tt.expected {
+				t.Errorf("Expected %v (%T), got %v (%T)", tt.expected, tt.expected, value, value)
+			}
+		})
+	}
+}
+
+// TestMongoDeletedFilter tests restricting filters to the soft-delete scope
+func TestMongoDeletedFilter(t *testing.T) {
+	s, err := mongoSchema(&Article{})
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	filter := bson.D{{Key: "author", Value: "alice"}}
+
+	tests := []struct {
+		name     string
+		scope    DeletedScope
+		filter   bson.D
+		expected bson.D
+	}{
+		{
+			name:     "Default scope",
+			scope:    ExcludeDeleted,
+			filter:   bson.D{},
+			expected: bson.D{{Key: "deleted_at", Value: nil}},
+		},
+		{
+			name:     "Default scope with filter",
+			scope:    ExcludeDeleted,
+			filter:   filter,
+			expected: bson.D{{Key: "$and", Value: bson.A{filter, bson.D{{Key: "deleted_at", Value: nil}}}}},
+		},
+		{
+			name:     "With deleted",
+			scope:    IncludeDeleted,
+			filter:   filter,
+			expected: filter,
+		},
+		{
+			name:     "Only deleted",
+			scope:    OnlyDeleted,
+			filter:   bson.D{},
+			expected: bson.D{{Key: "deleted_at", Value: bson.M{"$ne": nil}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := mongoDeletedFilter(s, tt.filter, tt.scope)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestErrors_TranslateMongo tests the MongoDB error mapping
+func TestErrors_TranslateMongo(t *testing.T) {
+	if err := translateError(mongo.ErrNoDocuments); !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Expected ErrDoesNotExist, got %v", err)
+	}
+
+	mongoErr := mongo.WriteException{WriteErrors: []mongo.WriteError{{
+		Code:    11000,
+		Message: `E11000 duplicate key error collection: app.users index: username_1 dup key: { username: "admin" }`,
+	}}}
+	err := translateError(mongoErr)
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Fatalf("Expected ErrUniqueViolation, got %v", err)
+	}
+
+	var integrityErr *IntegrityError
+	if !errors.As(err, &integrityErr) {
+		t.Fatalf("Expected *IntegrityError, got %T", err)
+	}
+	if integrityErr.Table != "users" || integrityErr.Constraint != "username_1" || integrityErr.Field != "username" {
+		t.Errorf("Expected users/username_1/username, got %s/%s/%s",
+			integrityErr.Table, integrityErr.Constraint, integrityErr.Field)
+	}
+	if !errors.As(err, &mongo.WriteException{}) {
+		t.Error("Expected original mongo.WriteException to be unwrappable")
+	}
+}
+
+// setupMongoReplicaSet connects like setupMongoDB, skipping the test when
+// the server is a standalone mongod, which does not support transactions.
+// A MONGODB_URI naming a replicaSet must point at a replica set.
+func setupMongoReplicaSet(t *testing.T) *Connection {
+	connection := setupMongoDB(t)
+
+	var hello bson.M
+	err := connection.MongoDB.RunCommand(context.Background(), bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		t.Fatalf("Failed to query server: %v", err)
+	}
+	if _, replicaSet := hello["setName"]; !replicaSet && hello["msg"] != "isdbgrid" {
+		if uri, err := url.Parse(os.Getenv("MONGODB_URI")); err == nil && uri.Query().Has("replicaSet") {
+			t.Fatalf("MONGODB_URI names a replica set, but %v is a standalone mongod", uri.Host)
+		}
+		t.Skip("MongoDB transactions require a replica set or sharded cluster")
+	}
+
+	return connection
+}
+
+// TestMongo_Transaction tests committing and aborting transactions
+func TestMongo_Transaction(t *testing.T) {
+	accessor := createMongoArticles(t, NewAccessor(setupMongoReplicaSet(t)))
+
+	err := accessor.Transaction(func(tx *Accessor) error {
+		if err := tx.Create(&Article{Title: "Committed"}); err != nil {
+			return err
+		}
+		return tx.Delete(&Article{BaseModel: BaseModel{ID: 1}})
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+
+	errRollback := errors.New("rollback")
+	err = accessor.Transaction(func(tx *Accessor) error {
+		if err := tx.Create(&Article{Title: "Aborted"}); err != nil {
+			return err
+		}
+
+		// Reads inside the transaction see its writes
+		count, err := tx.Count(&Article{}, `{"title": "Aborted"}`)
+		if err != nil {
+			return err
+		}
+		if count != 1 {
+			t.Errorf("Expected the transaction to see its article, got %d", count)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("Expected rollback error, got %v", err)
+	}
+
+	tests := []struct {
+		condition string
+		expected  int64
+	}{
+		{condition: `{"title": "Committed"}`, expected: 1},
+		{condition: `{"title": "Aborted"}`, expected: 0},
+		{condition: `{"title": "Go Basics"}`, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			count, err := accessor.Count(&Article{}, tt.condition)
+			if err != nil {
+				t.Fatalf("Failed to count articles: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("Expected %d articles, got %d", tt.expected, count)
+			}
+		})
+	}
+}
+
+// TestMongo_TransactionSession tests binding backends to the session
+func TestMongo_TransactionSession(t *testing.T) {
+	// Connecting is lazy, so sessions can be started without a server
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:1"))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	session, err := client.StartSession()
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	defer session.EndSession(context.Background())
+
+	backend := &mongoBackend{client: client, database: client.Database("gobase_test"), session: session}
+	if mongo.SessionFromContext(backend.context(context.TODO())) != session {
+		t.Error("Expected the backend to bind contexts to its session")
+	}
+
+	accessor := NewAccessor(NewConnection(mongoDBType, backend))
+	errNested := errors.New("nested")
+	err = accessor.Transaction(func(tx *Accessor) error {
+		if tx.connection.Backend != backend {
+			t.Error("Expected the nested transaction to join the outer one")
+		}
+		return errNested
+	})
+	if !errors.Is(err, errNested) {
+		t.Errorf("Expected nested error, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package gobase
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/gorm/schema"
+)
+
+// mongoComparisonOperators maps the comparison lookups to their MongoDB
+// query operators.
+var mongoComparisonOperators = map[string]string{
+	LookupExact: "$eq",
+	LookupGt:    "$gt",
+	LookupGte:   "$gte",
+	LookupLt:    "$lt",
+	LookupLte:   "$lte",
+	LookupIn:    "$in",
+}
+
+// compileMongoConditions turns a map of lookups into a MongoDB query
+// document, joining several conditions with $and. Field names are resolved
+// against the model schema, and keys are sorted so the generated query is
+// stable.
+func compileMongoConditions(s *schema.Schema, conditions map[string]interface{}) (bson.D, error) {
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filters := make(bson.A, 0, len(keys))
+	for _, key := range keys {
+		l, err := parseLookup(key)
+		if err != nil {
+			return nil, err
+		}
+
+		field := s.LookUpField(l.field)
+		if field == nil || field.DBName == "" {
+			return nil, &FieldError{Field: l.field, Model: s.Name}
+		}
+
+		filter, err := l.mongoFilter(mongoKey(s, field), conditions[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %w", key, err)
+		}
+		filters = append(filters, filter)
+	}
+
+	switch len(filters) {
+	case 0:
+		return bson.D{}, nil
+	case 1:
+		return filters[0].(bson.D), nil
+	}
+	return bson.D{{Key: "$and", Value: filters}}, nil
+}
+
+// mongoFilter builds the MongoDB query document for the lookup applied to
+// the document key.
+func (l lookup) mongoFilter(key string, value interface{}) (bson.D, error) {
+	if l.transform != "" {
+		return l.mongoTransformFilter(key, value)
+	}
+
+	switch l.operator {
+	case LookupExact:
+		return bson.D{{Key: key, Value: value}}, nil
+	case LookupGt, LookupGte, LookupLt, LookupLte:
+		return bson.D{{Key: key, Value: bson.D{{Key: mongoComparisonOperators[l.operator], Value: value}}}}, nil
+	case LookupIn:
+		values, err := sliceValues(value)
+		if err != nil {
+			return nil, err
+		}
+		return bson.D{{Key: key, Value: bson.D{{Key: "$in", Value: values}}}}, nil
+	case LookupRange:
+		values, err := rangeValues(value)
+		if err != nil {
+			return nil, err
+		}
+		return bson.D{{Key: key, Value: bson.D{{Key: "$gte", Value: values[0]}, {Key: "$lte", Value: values[1]}}}}, nil
+	case LookupIsNull:
+		isNull, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("isnull requires a bool, got %T", value)
+		}
+		// Missing keys count as null, like NULL columns
+		if isNull {
+			return bson.D{{Key: key, Value: nil}}, nil
+		}
+		return bson.D{{Key: key, Value: bson.D{{Key: "$ne", Value: nil}}}}, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s requires a string, got %T", l.operator, value)
+	}
+	return bson.D{{Key: key, Value: mongoPattern(l.operator, s)}}, nil
+}
+
+// mongoTransformFilter builds an $expr query comparing the transformed
+// document key, e.g. {$expr: {$gte: [{$year: "$created_at"}, 2024]}}.
+func (l lookup) mongoTransformFilter(key string, value interface{}) (bson.D, error) {
+	var lhs bson.D
+	switch l.transform {
+	case TransformDate:
+		lhs = bson.D{{Key: "$dateToString", Value: bson.D{{Key: "format", Value: "%Y-%m-%d"}, {Key: "date", Value: "$" + key}}}}
+		value = dateValue(value)
+	case TransformYear:
+		lhs = bson.D{{Key: "$year", Value: "$" + key}}
+	case TransformMonth:
+		lhs = bson.D{{Key: "$month", Value: "$" + key}}
+	}
+
+	compare := func(operator string, value interface{}) bson.D {
+		return bson.D{{Key: operator, Value: bson.A{lhs, value}}}
+	}
+
+	switch l.operator {
+	case LookupIn:
+		values, err := sliceValues(value)
+		if err != nil {
+			return nil, err
+		}
+		return bson.D{{Key: "$expr", Value: compare("$in", values)}}, nil
+	case LookupRange:
+		values, err := rangeValues(value)
+		if err != nil {
+			return nil, err
+		}
+		return bson.D{{Key: "$expr", Value: bson.D{{Key: "$and", Value: bson.A{
+			compare("$gte", values[0]),
+			compare("$lte", values[1]),
+		}}}}}, nil
+	}
+	return bson.D{{Key: "$expr", Value: compare(mongoComparisonOperators[l.operator], value)}}, nil
+}
+
+// mongoPattern builds the $regex matching the text lookups (iexact,
+// contains, startswith, endswith and their case-insensitive variants). The
+// value is matched literally.
+func mongoPattern(operator, value string) bson.D {
+	pattern := regexp.QuoteMeta(value)
+	switch operator {
+	case LookupIExact:
+		pattern = "^" + pattern + "$"
+	case LookupStartsWith, LookupIStartsWith:
+		pattern = "^" + pattern
+	case LookupEndsWith, LookupIEndsWith:
+		pattern += "$"
+	}
+
+	regex := bson.D{{Key: "$regex", Value: pattern}}
+	switch operator {
+	case LookupIExact, LookupIContains, LookupIStartsWith, LookupIEndsWith:
+		regex = append(regex, bson.E{Key: "$options", Value: "i"})
+	}
+	return regex
+}
@@ -0,0 +1,189 @@
+package gobase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestCompileMongoConditions tests translating lookups to query documents
+func TestCompileMongoConditions(t *testing.T) {
+	s, err := mongoSchema(&Article{})
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	year := bson.D{{Key: "$year", Value: "$created_at"}}
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   bson.D
+	}{
+		{
+			name:       "exact",
+			conditions: map[string]interface{}{"author": "alice"},
+			expected:   bson.D{{Key: "author", Value: "alice"}},
+		},
+		{
+			name:       "primary key",
+			conditions: map[string]interface{}{"id": 3},
+			expected:   bson.D{{Key: "_id", Value: 3}},
+		},
+		{
+			name:       "gt",
+			conditions: map[string]interface{}{"views__gt": 10},
+			expected:   bson.D{{Key: "views", Value: bson.D{{Key: "$gt", Value: 10}}}},
+		},
+		{
+			name:       "in",
+			conditions: map[string]interface{}{"author__in": []string{"bob", "carol"}},
+			expected:   bson.D{{Key: "author", Value: bson.D{{Key: "$in", Value: []interface{}{"bob", "carol"}}}}},
+		},
+		{
+			name:       "range",
+			conditions: map[string]interface{}{"views__range": []int{5, 30}},
+			expected:   bson.D{{Key: "views", Value: bson.D{{Key: "$gte", Value: 5}, {Key: "$lte", Value: 30}}}},
+		},
+		{
+			name:       "icontains",
+			conditions: map[string]interface{}{"title__icontains": "go."},
+			expected:   bson.D{{Key: "title", Value: bson.D{{Key: "$regex", Value: `go\.`}, {Key: "$options", Value: "i"}}}},
+		},
+		{
+			name:       "startswith",
+			conditions: map[string]interface{}{"title__startswith": "Go"},
+			expected:   bson.D{{Key: "title", Value: bson.D{{Key: "$regex", Value: "^Go"}}}},
+		},
+		{
+			name:       "isnull",
+			conditions: map[string]interface{}{"deleted_at__isnull": false},
+			expected:   bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$ne", Value: nil}}}},
+		},
+		{
+			name:       "year gte",
+			conditions: map[string]interface{}{"created_at__year__gte": 2021},
+			expected:   bson.D{{Key: "$expr", Value: bson.D{{Key: "$gte", Value: bson.A{year, 2021}}}}},
+		},
+		{
+			name:       "combined",
+			conditions: map[string]interface{}{"status": "published", "author": "bob"},
+			expected: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: "author", Value: "bob"}},
+				bson.D{{Key: "status", Value: "published"}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := compileMongoConditions(s, tt.conditions)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(filter, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, filter)
+			}
+		})
+	}
+}
+
+// TestMongo_FilterLookupErrors tests invalid lookups and values on MongoDB
+func TestMongo_FilterLookupErrors(t *testing.T) {
+	accessor := NewAccessor(NewConnection(mongoDBType, &mongoBackend{}))
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+	}{
+		{name: "unknown field", conditions: map[string]interface{}{"missing": 1}},
+		{name: "unknown lookup", conditions: map[string]interface{}{"views__between": 1}},
+		{name: "in without slice", conditions: map[string]interface{}{"views__in": 1}},
+		{name: "range with wrong length", conditions: map[string]interface{}{"views__range": []int{1}}},
+		{name: "isnull without bool", conditions: map[string]interface{}{"deleted_at__isnull": "yes"}},
+		{name: "contains without string", conditions: map[string]interface{}{"title__contains": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			if err := accessor.Filter(&articles, tt.conditions); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+// TestMongo_FilterLookups tests field lookups against MongoDB
+func TestMongo_FilterLookups(t *testing.T) {
+	accessor := setupMongoArticles(t)
+
+	old := &Article{Title: "100% Go.Lang", Author: "Dave", Status: "archived", Views: 5}
+	old.CreatedAt = time.Date(2020, time.March, 15, 12, 0, 0, 0, time.UTC)
+	if err := accessor.Create(old); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	deleted := &Article{Title: "Deleted Go", Author: "alice", Status: "published", Views: 99}
+	if err := accessor.Create(deleted); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	if err := accessor.Delete(deleted); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   int
+	}{
+		{name: "exact", conditions: map[string]interface{}{"author__exact": "alice"}, expected: 2},
+		{name: "iexact", conditions: map[string]interface{}{"author__iexact": "DAVE"}, expected: 1},
+		{name: "contains is case-sensitive", conditions: map[string]interface{}{"title__contains": "GO"}, expected: 0},
+		{name: "contains", conditions: map[string]interface{}{"title__contains": "Go"}, expected: 3},
+		{name: "icontains", conditions: map[string]interface{}{"title__icontains": "go"}, expected: 4},
+		{name: "contains escapes metacharacters", conditions: map[string]interface{}{"title__contains": "0% Go."}, expected: 1},
+		{name: "startswith", conditions: map[string]interface{}{"title__startswith": "Go"}, expected: 1},
+		{name: "istartswith", conditions: map[string]interface{}{"title__istartswith": "d"}, expected: 2},
+		{name: "endswith", conditions: map[string]interface{}{"title__endswith": "Go"}, expected: 1},
+		{name: "iendswith", conditions: map[string]interface{}{"title__iendswith": "LANG"}, expected: 1},
+		{name: "gt", conditions: map[string]interface{}{"views__gt": 10}, expected: 2},
+		{name: "gte", conditions: map[string]interface{}{"views__gte": 10}, expected: 3},
+		{name: "lt", conditions: map[string]interface{}{"views__lt": 10}, expected: 2},
+		{name: "lte", conditions: map[string]interface{}{"views__lte": 10}, expected: 3},
+		{name: "in", conditions: map[string]interface{}{"author__in": []string{"bob", "carol"}}, expected: 2},
+		{name: "empty in", conditions: map[string]interface{}{"author__in": []string{}}, expected: 0},
+		{name: "range", conditions: map[string]interface{}{"views__range": []int{5, 30}}, expected: 3},
+		{name: "isnull", conditions: map[string]interface{}{"deleted_at__isnull": true}, expected: 5},
+		{name: "primary key", conditions: map[string]interface{}{"id__in": []int{1, 2}}, expected: 2},
+		{name: "year", conditions: map[string]interface{}{"created_at__year": 2020}, expected: 1},
+		{name: "year gte", conditions: map[string]interface{}{"created_at__year__gte": 2021}, expected: 4},
+		{name: "month", conditions: map[string]interface{}{"created_at__month": 3, "created_at__year": 2020}, expected: 1},
+		{name: "date", conditions: map[string]interface{}{"created_at__date": old.CreatedAt}, expected: 1},
+		{name: "date string", conditions: map[string]interface{}{"created_at__date": "2020-03-15"}, expected: 1},
+		{name: "combined", conditions: map[string]interface{}{"status": "published", "views__gt": 20}, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			err := accessor.Filter(&articles, tt.conditions)
+			if err != nil {
+				t.Fatalf("Filter failed: %v", err)
+			}
+
+			if len(articles) != tt.expected {
+				t.Errorf("Expected %d articles, got %d", tt.expected, len(articles))
+			}
+		})
+	}
+
+	count, err := accessor.OnlyDeleted().CountFilter(&Article{}, map[string]interface{}{"title__icontains": "go"})
+	if err != nil {
+		t.Fatalf("CountFilter failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 deleted article, got %d", count)
+	}
+}
@@ -0,0 +1,188 @@
+package gobase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// MongoValidator is implemented by models whose MongoDB collection
+// validates the documents written to it. Migrate creates the collection
+// with the returned validator, or updates the validator of an existing
+// collection:
+//
+//	func (Article) MongoValidator() (bson.M, error) {
+//		return gobase.MongoJSONSchema(&Article{})
+//	}
+type MongoValidator interface {
+	MongoValidator() (bson.M, error)
+}
+
+// MongoJSONSchema builds a $jsonSchema validator from the model's fields:
+// the BSON type of each key, the maximum length of sized strings, and the
+// keys of primary key and not null fields as required.
+func MongoJSONSchema(model interface{}) (bson.M, error) {
+	s, err := mongoSchema(model)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := bson.M{}
+	required := bson.A{}
+	for _, name := range s.DBNames {
+		field := s.FieldsByDBName[name]
+		key := mongoKey(s, field)
+		if field.PrimaryKey || field.NotNull {
+			required = append(required, key)
+		}
+
+		property := mongoFieldSchema(field)
+		if property != nil {
+			properties[key] = property
+		}
+	}
+
+	return bson.M{"$jsonSchema": bson.M{
+		"bsonType":   "object",
+		"required":   required,
+		"properties": properties,
+	}}, nil
+}
+
+// mongoFieldSchema returns the JSON schema of a field's key, or nil for
+// types that are not checked, such as custom types stored via
+// driver.Valuer.
+func mongoFieldSchema(field *schema.Field) bson.M {
+	fieldType := field.IndirectFieldType
+	nullable := field.FieldType.Kind() == reflect.Ptr
+
+	var bsonTypes bson.A
+	switch {
+	case fieldType == reflect.TypeOf(primitive.ObjectID{}):
+		bsonTypes = bson.A{"objectId"}
+	case fieldType == reflect.TypeOf(time.Time{}):
+		bsonTypes = bson.A{"date"}
+	case fieldType == reflect.TypeOf(gorm.DeletedAt{}):
+		bsonTypes, nullable = bson.A{"date"}, true
+	case fieldType.Kind() == reflect.Bool:
+		bsonTypes = bson.A{"bool"}
+	case fieldType.Kind() >= reflect.Int && fieldType.Kind() <= reflect.Uint64:
+		// Small integers are stored as int, others as long
+		bsonTypes = bson.A{"int", "long"}
+	case fieldType.Kind() == reflect.Float32 || fieldType.Kind() == reflect.Float64:
+		bsonTypes = bson.A{"double"}
+	case fieldType.Kind() == reflect.String:
+		bsonTypes = bson.A{"string"}
+	case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Uint8:
+		bsonTypes = bson.A{"binData"}
+	default:
+		return nil
+	}
+	if nullable {
+		bsonTypes = append(bsonTypes, "null")
+	}
+
+	property := bson.M{"bsonType": bsonTypes}
+	if fieldType.Kind() == reflect.String && field.Size > 0 {
+		property["maxLength"] = field.Size
+	}
+	return property
+}
+
+// mongoIndexModels returns the MongoDB indexes declared in the model's
+// gorm tags: index and uniqueIndex, with their names, composite fields and
+// sort order, and unique fields. The primary key is always indexed as _id.
+// Partial (where) indexes are created on every document.
+func mongoIndexModels(s *schema.Schema) []mongo.IndexModel {
+	var indexes []mongo.IndexModel
+	for _, index := range s.ParseIndexes() {
+		keys := bson.D{}
+		for _, option := range index.Fields {
+			order := 1
+			if strings.EqualFold(option.Sort, "desc") {
+				order = -1
+			}
+			keys = append(keys, bson.E{Key: mongoKey(s, option.Field), Value: order})
+		}
+
+		opts := options.Index().SetName(index.Name)
+		if index.Class == "UNIQUE" {
+			opts.SetUnique(true)
+		}
+		indexes = append(indexes, mongo.IndexModel{Keys: keys, Options: opts})
+	}
+
+	for _, field := range s.Fields {
+		if field.Unique && !field.PrimaryKey && field.DBName != "" {
+			indexes = append(indexes, mongo.IndexModel{
+				Keys:    bson.D{{Key: field.DBName, Value: 1}},
+				Options: options.Index().SetName(mongoNamer.UniqueName(s.Table, field.DBName)).SetUnique(true),
+			})
+		}
+	}
+	return indexes
+}
+
+// Migrate implements Backend: it creates the models' collections, with the
+// validators of models implementing MongoValidator, and builds their
+// indexes.
+func (b *mongoBackend) Migrate(ctx context.Context, models []interface{}) error {
+	ctx = b.context(ctx)
+	for _, model := range models {
+		s, err := mongoSchema(model)
+		if err != nil {
+			return err
+		}
+		if err := b.ensureCollection(ctx, s, model); err != nil {
+			return fmt.Errorf("failed to create collection %s: %w", s.Table, err)
+		}
+
+		indexes := mongoIndexModels(s)
+		if len(indexes) == 0 {
+			continue
+		}
+		if _, err := b.collection(s).Indexes().CreateMany(ctx, indexes); err != nil {
+			return fmt.Errorf("failed to create indexes on %s: %w", s.Table, translateError(err))
+		}
+	}
+	return nil
+}
+
+// ensureCollection creates the model's collection if it does not exist,
+// and sets the validator of models implementing MongoValidator.
+func (b *mongoBackend) ensureCollection(ctx context.Context, s *schema.Schema, model interface{}) error {
+	var validator bson.M
+	if validated, ok := model.(MongoValidator); ok {
+		var err error
+		if validator, err = validated.MongoValidator(); err != nil {
+			return err
+		}
+	}
+
+	names, err := b.database.ListCollectionNames(ctx, bson.D{{Key: "name", Value: s.Table}})
+	if err != nil {
+		return translateError(err)
+	}
+	if len(names) == 0 {
+		opts := options.CreateCollection()
+		if validator != nil {
+			opts.SetValidator(validator)
+		}
+		return translateError(b.database.CreateCollection(ctx, s.Table, opts))
+	}
+
+	if validator == nil {
+		return nil
+	}
+	command := bson.D{{Key: "collMod", Value: s.Table}, {Key: "validator", Value: validator}}
+	return translateError(b.database.RunCommand(ctx, command).Err())
+}
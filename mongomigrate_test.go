@@ -0,0 +1,169 @@
+package gobase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Gadget is a test model with a MongoDB validator
+type Gadget struct {
+	BaseModel
+	Name   string  `gorm:"size:10;not null" json:"name"`
+	Serial string  `gorm:"unique" json:"serial"`
+	Price  float64 `json:"price"`
+}
+
+// MongoValidator validates gadgets against their fields
+func (Gadget) MongoValidator() (bson.M, error) {
+	return MongoJSONSchema(&Gadget{})
+}
+
+// TestMongoIndexModels tests deriving indexes from gorm tags
+func TestMongoIndexModels(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    interface{}
+		expected map[string]bool
+		keys     map[string]bson.D
+	}{
+		{
+			name:     "User",
+			model:    &User{},
+			expected: map[string]bool{"idx_users_deleted_at": false, "idx_users_username": true, "idx_users_email": true},
+			keys:     map[string]bson.D{"idx_users_username": {{Key: "username", Value: 1}}},
+		},
+		{
+			name:     "Unique field",
+			model:    &Gadget{},
+			expected: map[string]bool{"idx_gadgets_deleted_at": false, "uni_gadgets_serial": true},
+			keys:     map[string]bson.D{"uni_gadgets_serial": {{Key: "serial", Value: 1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := mongoSchema(tt.model)
+			if err != nil {
+				t.Fatalf("Failed to parse schema: %v", err)
+			}
+
+			indexes := mongoIndexModels(s)
+			if len(indexes) != len(tt.expected) {
+				t.Fatalf("Expected %d indexes, got %d", len(tt.expected), len(indexes))
+			}
+			for _, index := range indexes {
+				name := *index.Options.Name
+				unique, ok := tt.expected[name]
+				if !ok {
+					t.Errorf("Unexpected index %s", name)
+					continue
+				}
+				if (index.Options.Unique != nil && *index.Options.Unique) != unique {
+					t.Errorf("Expected index %s unique=%v", name, unique)
+				}
+				if keys, ok := tt.keys[name]; ok && !reflect.DeepEqual(index.Keys, keys) {
+					t.Errorf("Expected index %s keys %v, got %v", name, keys, index.Keys)
+				}
+			}
+		})
+	}
+}
+
+// TestMongoJSONSchema tests building validators from model fields
+func TestMongoJSONSchema(t *testing.T) {
+	validator, err := MongoJSONSchema(&User{})
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	jsonSchema := validator["$jsonSchema"].(bson.M)
+	expectedRequired := bson.A{"_id", "username", "email", "password_hash"}
+	if !reflect.DeepEqual(jsonSchema["required"], expectedRequired) {
+		t.Errorf("Expected required %v, got %v", expectedRequired, jsonSchema["required"])
+	}
+
+	properties := jsonSchema["properties"].(bson.M)
+	tests := []struct {
+		key      string
+		expected bson.M
+	}{
+		{key: "_id", expected: bson.M{"bsonType": bson.A{"int", "long"}}},
+		{key: "username", expected: bson.M{"bsonType": bson.A{"string"}, "maxLength": 150}},
+		{key: "is_active", expected: bson.M{"bsonType": bson.A{"bool"}}},
+		{key: "created_at", expected: bson.M{"bsonType": bson.A{"date"}}},
+		{key: "deleted_at", expected: bson.M{"bsonType": bson.A{"date", "null"}}},
+		{key: "last_login", expected: bson.M{"bsonType": bson.A{"date", "null"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if !reflect.DeepEqual(properties[tt.key], tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, properties[tt.key])
+			}
+		})
+	}
+}
+
+// TestMongo_Migrate tests creating collections, indexes and validators
+func TestMongo_Migrate(t *testing.T) {
+	accessor := NewAccessor(setupMongoDB(t))
+
+	// Migrating twice must be a no-op the second time
+	for i := 0; i < 2; i++ {
+		if err := accessor.Migrate(&User{}, &Gadget{}); err != nil {
+			t.Fatalf("Failed to migrate: %v", err)
+		}
+	}
+
+	names, err := accessor.connection.MongoDB.ListCollectionNames(accessor.Context(), bson.D{})
+	if err != nil {
+		t.Fatalf("Failed to list collections: %v", err)
+	}
+	collections := map[string]bool{}
+	for _, name := range names {
+		collections[name] = true
+	}
+	if !collections["users"] || !collections["gadgets"] {
+		t.Errorf("Expected users and gadgets collections, got %v", names)
+	}
+
+	var indexes []bson.M
+	cursor, err := accessor.connection.MongoDB.Collection("users").Indexes().List(accessor.Context())
+	if err != nil {
+		t.Fatalf("Failed to list indexes: %v", err)
+	}
+	if err := cursor.All(accessor.Context(), &indexes); err != nil {
+		t.Fatalf("Failed to list indexes: %v", err)
+	}
+	indexNames := map[string]bool{}
+	for _, index := range indexes {
+		indexNames[index["name"].(string)] = true
+	}
+	for _, name := range []string{"idx_users_username", "idx_users_email", "idx_users_deleted_at"} {
+		if !indexNames[name] {
+			t.Errorf("Expected index %s, got %v", name, indexNames)
+		}
+	}
+
+	if err := accessor.Create(&User{Username: "alice", Email: "alice@example.com", PasswordHash: "x"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	err = accessor.Create(&User{Username: "alice", Email: "other@example.com", PasswordHash: "x"})
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Fatalf("Expected ErrUniqueViolation, got %v", err)
+	}
+	var integrityErr *IntegrityError
+	if errors.As(err, &integrityErr) && integrityErr.Field != "username" {
+		t.Errorf("Expected field username, got %s", integrityErr.Field)
+	}
+
+	if err := accessor.Create(&Gadget{Name: "Widget", Serial: "A1", Price: 9.5}); err != nil {
+		t.Fatalf("Failed to create gadget: %v", err)
+	}
+	if err := accessor.Create(&Gadget{Name: "Far too long a name", Serial: "A2"}); err == nil {
+		t.Error("Expected the validator to reject a name longer than 10 characters")
+	}
+}
@@ -0,0 +1,256 @@
+package gobase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Page describes one page of results from Paginate, similar to Django's
+// Paginator.page(). Page numbers start at 1.
+type Page struct {
+	Number      int   // current page number
+	PageSize    int   // maximum number of records per page
+	TotalCount  int64 // number of records across all pages
+	NumPages    int   // total number of pages, at least 1
+	HasNext     bool  // whether a page follows this one
+	HasPrevious bool  // whether a page precedes this one
+}
+
+// CursorPage describes one page of results from CursorPaginate.
+type CursorPage struct {
+	PageSize   int    // maximum number of records per page
+	HasNext    bool   // whether more records follow this page
+	NextCursor string // token for the next page, empty when HasNext is false
+}
+
+// cursorKey is the position encoded in a cursor token: the (created_at, id)
+// of the last record on a page.
+type cursorKey struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uint      `json:"i"`
+}
+
+// Paginate populates models with the requested page of the query and
+// returns the page metadata. Any Limit or Offset on the QuerySet is replaced
+// by the page window. Without an explicit ordering records are ordered by
+// primary key so that pages do not overlap.
+//
+// A page number outside 1..NumPages returns ErrInvalidPage. The first page
+// of an empty result is valid and simply contains no records.
+func (qs *QuerySet) Paginate(models interface{}, page, pageSize int) (*Page, error) {
+	if pageSize < 1 {
+		return nil, fmt.Errorf("%w: page size must be at least 1, got %d", ErrInvalidPage, pageSize)
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("%w: page number must be at least 1, got %d", ErrInvalidPage, page)
+	}
+
+	unsliced := qs.Limit(-1).Offset(0)
+	if len(unsliced.orders) == 0 {
+		unsliced = unsliced.OrderBy("id")
+	}
+
+	total, err := unsliced.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	numPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	if numPages == 0 {
+		numPages = 1
+	}
+	if page > numPages {
+		return nil, fmt.Errorf("%w: page %d is out of range (1-%d)", ErrInvalidPage, page, numPages)
+	}
+
+	if err := unsliced.Limit(pageSize).Offset((page - 1) * pageSize).All(models); err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		Number:      page,
+		PageSize:    pageSize,
+		TotalCount:  total,
+		NumPages:    numPages,
+		HasNext:     page < numPages,
+		HasPrevious: page > 1,
+	}, nil
+}
+
+// CursorPaginate populates models with up to pageSize records following the
+// position encoded in cursor, using keyset pagination over
+// (created_at, id). Pass an empty cursor for the first page and
+// CursorPage.NextCursor for the following ones.
+//
+// Unlike Paginate, keyset pages never skip or repeat records when rows are
+// inserted concurrently, and their cost does not grow with the page depth.
+// Records are returned oldest first; order the QuerySet by "-created_at" to
+// page newest first. Any other ordering, Limit or Offset is replaced.
+//
+// A cursor that cannot be decoded returns ErrInvalidCursor.
+func (qs *QuerySet) CursorPaginate(models interface{}, cursor string, pageSize int) (*CursorPage, error) {
+	if pageSize < 1 {
+		return nil, fmt.Errorf("%w: page size must be at least 1, got %d", ErrInvalidPage, pageSize)
+	}
+
+	rv := reflect.ValueOf(models)
+	if models == nil || rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return nil, errors.New("models must be a pointer to a slice")
+	}
+
+	descending := len(qs.orders) > 0 && qs.orders[0] == "-created_at"
+	keyset := qs.Limit(pageSize + 1).Offset(0)
+	if descending {
+		keyset = keyset.OrderBy("-created_at", "-id")
+	} else {
+		keyset = keyset.OrderBy("created_at", "id")
+	}
+
+	if cursor != "" {
+		key, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		keyset = keyset.Filter(keysetCondition{key: key, descending: descending})
+	}
+
+	// Fetch one extra record to find out whether another page follows
+	if err := keyset.All(models); err != nil {
+		return nil, err
+	}
+
+	results := rv.Elem()
+	page := &CursorPage{PageSize: pageSize}
+	if results.Len() <= pageSize {
+		return page, nil
+	}
+
+	results.Set(results.Slice(0, pageSize))
+	next, err := qs.accessor.cursorKeyOf(qs.model, results.Index(pageSize-1))
+	if err != nil {
+		return nil, err
+	}
+
+	page.HasNext = true
+	page.NextCursor = encodeCursor(next)
+	return page, nil
+}
+
+// Paginate populates models with the requested page of all records of the
+// slice's element type. See QuerySet.Paginate.
+func (a *Accessor) Paginate(models interface{}, page, pageSize int) (*Page, error) {
+	model, err := a.sliceModel(models)
+	if err != nil {
+		return nil, err
+	}
+	return a.Objects(model).Paginate(models, page, pageSize)
+}
+
+// CursorPaginate populates models with the page of records following
+// cursor, oldest first. See QuerySet.CursorPaginate.
+func (a *Accessor) CursorPaginate(models interface{}, cursor string, pageSize int) (*CursorPage, error) {
+	model, err := a.sliceModel(models)
+	if err != nil {
+		return nil, err
+	}
+	return a.Objects(model).CursorPaginate(models, cursor, pageSize)
+}
+
+// cursorKeyOf reads the (created_at, id) position of a record.
+func (a *Accessor) cursorKeyOf(model interface{}, record reflect.Value) (cursorKey, error) {
+	s, err := a.modelSchema(model)
+	if err != nil {
+		return cursorKey{}, err
+	}
+
+	createdAtField := s.LookUpField("created_at")
+	idField := s.LookUpField("id")
+	if createdAtField == nil || idField == nil {
+		return cursorKey{}, fmt.Errorf("model '%s' has no created_at or id field", s.Name)
+	}
+
+	createdAt, _ := createdAtField.ValueOf(a.Context(), record)
+	id, _ := idField.ValueOf(a.Context(), record)
+
+	key := cursorKey{}
+	key.CreatedAt, _ = createdAt.(time.Time)
+	key.ID, _ = id.(uint)
+	return key, nil
+}
+
+// encodeCursor serializes a keyset position into an opaque URL-safe token.
+func encodeCursor(key cursorKey) string {
+	data, _ := json.Marshal(key)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a token produced by encodeCursor.
+func decodeCursor(cursor string) (cursorKey, error) {
+	var key cursorKey
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return key, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(data, &key); err != nil {
+		return key, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if key.CreatedAt.IsZero() || key.ID == 0 {
+		return key, fmt.Errorf("%w: missing position", ErrInvalidCursor)
+	}
+
+	return key, nil
+}
+
+// keysetCondition matches the records after a keyset position:
+// created_at > c OR (created_at = c AND id > i), or the reverse for
+// descending pages.
+type keysetCondition struct {
+	key        cursorKey
+	descending bool
+}
+
+// And returns a condition matching when k and all others match.
+func (k keysetCondition) And(others ...Condition) Condition {
+	return combineConditions(clause.AndWithSpace, k, others)
+}
+
+// Or returns a condition matching when k or any of the others match.
+func (k keysetCondition) Or(others ...Condition) Condition {
+	return combineConditions(clause.OrWithSpace, k, others)
+}
+
+// Not returns the negation of k.
+func (k keysetCondition) Not() Condition {
+	return &compositeCondition{connector: clause.AndWithSpace, children: []Condition{k}, negated: true}
+}
+
+// compile builds the keyset comparison for the model's columns.
+func (k keysetCondition) compile(dialect string, s *schema.Schema) (clause.Expression, error) {
+	createdAt, err := resolveField(s, "created_at")
+	if err != nil {
+		return nil, err
+	}
+	id, err := resolveField(s, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	operator := ">"
+	if k.descending {
+		operator = "<"
+	}
+
+	createdAtColumn := clause.Column{Name: createdAt}
+	return clause.Expr{
+		SQL:  fmt.Sprintf("(? %s ? OR (? = ? AND ? %s ?))", operator, operator),
+		Vars: []interface{}{createdAtColumn, k.key.CreatedAt, createdAtColumn, k.key.CreatedAt, clause.Column{Name: id}, k.key.ID},
+	}, nil
+}
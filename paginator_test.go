@@ -0,0 +1,207 @@
+package gobase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// setupPagedArticles creates count articles sharing a few created_at
+// timestamps, so keyset pagination has to break ties on id
+func setupPagedArticles(t *testing.T, count int) *Accessor {
+	accessor := NewAccessor(setupTestDB(t))
+
+	if err := accessor.Migrate(&Article{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= count; i++ {
+		article := &Article{Title: fmt.Sprintf("Article %d", i), Status: "published"}
+		article.CreatedAt = base.Add(time.Duration(i/3) * time.Minute)
+		if err := accessor.Create(article); err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+
+	return accessor
+}
+
+// TestQuerySet_Paginate tests page-based pagination metadata and windows
+func TestQuerySet_Paginate(t *testing.T) {
+	accessor := setupPagedArticles(t, 7)
+
+	tests := []struct {
+		name        string
+		page        int
+		pageSize    int
+		expected    []string
+		numPages    int
+		hasNext     bool
+		hasPrevious bool
+	}{
+		{name: "First page", page: 1, pageSize: 3, expected: []string{"Article 1", "Article 2", "Article 3"}, numPages: 3, hasNext: true},
+		{name: "Middle page", page: 2, pageSize: 3, expected: []string{"Article 4", "Article 5", "Article 6"}, numPages: 3, hasNext: true, hasPrevious: true},
+		{name: "Last partial page", page: 3, pageSize: 3, expected: []string{"Article 7"}, numPages: 3, hasPrevious: true},
+		{name: "Single page", page: 1, pageSize: 10, expected: []string{"Article 1", "Article 2", "Article 3", "Article 4", "Article 5", "Article 6", "Article 7"}, numPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			page, err := accessor.Objects(&Article{}).Limit(1).Paginate(&articles, tt.page, tt.pageSize)
+			if err != nil {
+				t.Fatalf("Paginate failed: %v", err)
+			}
+
+			if page.TotalCount != 7 {
+				t.Errorf("Expected total count 7, got %d", page.TotalCount)
+			}
+			if page.Number != tt.page || page.NumPages != tt.numPages {
+				t.Errorf("Expected page %d of %d, got %d of %d", tt.page, tt.numPages, page.Number, page.NumPages)
+			}
+			if page.HasNext != tt.hasNext || page.HasPrevious != tt.hasPrevious {
+				t.Errorf("Expected HasNext=%v HasPrevious=%v, got %v %v", tt.hasNext, tt.hasPrevious, page.HasNext, page.HasPrevious)
+			}
+
+			if len(articles) != len(tt.expected) {
+				t.Fatalf("Expected %d articles, got %d", len(tt.expected), len(articles))
+			}
+			for i, article := range articles {
+				if article.Title != tt.expected[i] {
+					t.Errorf("Expected %s at position %d, got %s", tt.expected[i], i, article.Title)
+				}
+			}
+		})
+	}
+}
+
+// TestQuerySet_PaginateInvalid tests out-of-range pages and empty results
+func TestQuerySet_PaginateInvalid(t *testing.T) {
+	accessor := setupPagedArticles(t, 4)
+
+	var articles []Article
+	for _, tt := range []struct{ page, pageSize int }{{0, 2}, {3, 2}, {1, 0}} {
+		if _, err := accessor.Paginate(&articles, tt.page, tt.pageSize); !errors.Is(err, ErrInvalidPage) {
+			t.Errorf("Expected ErrInvalidPage for page %d size %d, got %v", tt.page, tt.pageSize, err)
+		}
+	}
+
+	page, err := accessor.Objects(&Article{}).Filter(Q{"status": "draft"}).Paginate(&articles, 1, 2)
+	if err != nil {
+		t.Fatalf("Expected empty first page, got %v", err)
+	}
+	if len(articles) != 0 || page.TotalCount != 0 || page.NumPages != 1 || page.HasNext {
+		t.Errorf("Unexpected empty page: %+v with %d articles", page, len(articles))
+	}
+}
+
+// TestQuerySet_CursorPaginate tests keyset pagination over (created_at, id)
+func TestQuerySet_CursorPaginate(t *testing.T) {
+	tests := []struct {
+		name     string
+		orderBy  []string
+		expected []string
+	}{
+		{
+			name:     "Oldest first",
+			expected: []string{"Article 1", "Article 2", "Article 3", "Article 4", "Article 5", "Article 6", "Article 7", "Article 8"},
+		},
+		{
+			name:     "Newest first",
+			orderBy:  []string{"-created_at"},
+			expected: []string{"Article 8", "Article 7", "Article 6", "Article 5", "Article 4", "Article 3", "Article 2", "Article 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor := setupPagedArticles(t, 8)
+			qs := accessor.Objects(&Article{}).OrderBy(tt.orderBy...)
+
+			var titles []string
+			cursor := ""
+			for pages := 0; pages < 10; pages++ {
+				var articles []Article
+				page, err := qs.CursorPaginate(&articles, cursor, 3)
+				if err != nil {
+					t.Fatalf("CursorPaginate failed: %v", err)
+				}
+				for _, article := range articles {
+					titles = append(titles, article.Title)
+				}
+				if !page.HasNext {
+					break
+				}
+				cursor = page.NextCursor
+			}
+
+			if fmt.Sprint(titles) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, titles)
+			}
+		})
+	}
+}
+
+// TestQuerySet_CursorPaginateConcurrentInsert tests that inserts between
+// pages neither repeat nor skip records
+func TestQuerySet_CursorPaginateConcurrentInsert(t *testing.T) {
+	accessor := setupPagedArticles(t, 4)
+
+	var first []Article
+	page, err := accessor.CursorPaginate(&first, "", 2)
+	if err != nil {
+		t.Fatalf("CursorPaginate failed: %v", err)
+	}
+
+	// A record inserted before the cursor position must not shift the next page
+	early := &Article{Title: "Backdated"}
+	early.CreatedAt = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := accessor.Create(early); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	var second []Article
+	if _, err := accessor.CursorPaginate(&second, page.NextCursor, 2); err != nil {
+		t.Fatalf("CursorPaginate failed: %v", err)
+	}
+
+	if len(second) != 2 || second[0].Title != "Article 3" || second[1].Title != "Article 4" {
+		t.Errorf("Expected Article 3 and Article 4, got %v", second)
+	}
+}
+
+// TestQuerySet_CursorPaginateInvalid tests malformed cursor tokens
+func TestQuerySet_CursorPaginateInvalid(t *testing.T) {
+	accessor := setupPagedArticles(t, 2)
+
+	var articles []Article
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", encodeCursor(cursorKey{})} {
+		if _, err := accessor.CursorPaginate(&articles, cursor, 2); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", cursor, err)
+		}
+	}
+}
+
+// TestManager_Paginate tests the typed pagination helpers
+func TestManager_Paginate(t *testing.T) {
+	accessor := setupArticles(t)
+	articles := For[Article](accessor)
+
+	items, page, err := articles.Paginate(2, 1, Q{"status": "published"})
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if page.TotalCount != 3 || page.NumPages != 3 || len(items) != 1 || items[0].Title != "Advanced Go" {
+		t.Errorf("Unexpected page %+v with items %v", page, items)
+	}
+
+	items, cursorPage, err := articles.CursorPaginate("", 10, Q{"author": "alice"})
+	if err != nil {
+		t.Fatalf("CursorPaginate failed: %v", err)
+	}
+	if len(items) != 2 || cursorPage.HasNext || cursorPage.NextCursor != "" {
+		t.Errorf("Unexpected cursor page %+v with items %v", cursorPage, items)
+	}
+}
@@ -0,0 +1,176 @@
+package gobase
+
+import (
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Condition is a composable query condition accepted by QuerySet.Filter and
+// QuerySet.Exclude. Conditions are built from Q values and combined with
+// And, Or and Not, mirroring Django's Q objects:
+//
+//	cond := gobase.Q{"status": "published"}.
+//		Or(gobase.Q{"author": author}).
+//		And(gobase.Q{"deleted_by_moderator": true}.Not())
+type Condition interface {
+	// And returns a condition matching when this and all others match.
+	And(others ...Condition) Condition
+	// Or returns a condition matching when this or any of the others match.
+	Or(others ...Condition) Condition
+	// Not returns the negation of this condition.
+	Not() Condition
+
+	// compile builds the SQL expression for the condition. A nil
+	// expression means the condition matches every record.
+	compile(dialect string, s *schema.Schema) (clause.Expression, error)
+}
+
+// Q is a set of field lookups joined with AND, using the same syntax as
+// map-based filters (e.g. Q{"views__gte": 10, "status": "published"}).
+// An empty Q matches every record.
+type Q map[string]interface{}
+
+// And returns a condition matching when q and all others match.
+func (q Q) And(others ...Condition) Condition {
+	return combineConditions(clause.AndWithSpace, q, others)
+}
+
+// Or returns a condition matching when q or any of the others match.
+func (q Q) Or(others ...Condition) Condition {
+	return combineConditions(clause.OrWithSpace, q, others)
+}
+
+// Not returns the negation of q.
+func (q Q) Not() Condition {
+	return &compositeCondition{connector: clause.AndWithSpace, children: []Condition{q}, negated: true}
+}
+
+// compile builds the SQL expression for the lookups in q.
+func (q Q) compile(dialect string, s *schema.Schema) (clause.Expression, error) {
+	return compileConditions(dialect, s, q)
+}
+
+// compositeCondition joins child conditions with AND or OR, optionally
+// negating the result.
+type compositeCondition struct {
+	connector string
+	children  []Condition
+	negated   bool
+}
+
+// combineConditions joins first and others with the given connector.
+func combineConditions(connector string, first Condition, others []Condition) Condition {
+	children := make([]Condition, 0, len(others)+1)
+	children = append(children, first)
+	children = append(children, others...)
+	return &compositeCondition{connector: connector, children: children}
+}
+
+// And returns a condition matching when c and all others match.
+func (c *compositeCondition) And(others ...Condition) Condition {
+	return combineConditions(clause.AndWithSpace, c, others)
+}
+
+// Or returns a condition matching when c or any of the others match.
+func (c *compositeCondition) Or(others ...Condition) Condition {
+	return combineConditions(clause.OrWithSpace, c, others)
+}
+
+// Not returns the negation of c.
+func (c *compositeCondition) Not() Condition {
+	negated := *c
+	negated.negated = !c.negated
+	return &negated
+}
+
+// compile builds the SQL expression for c and its children.
+func (c *compositeCondition) compile(dialect string, s *schema.Schema) (clause.Expression, error) {
+	exprs := make([]clause.Expression, 0, len(c.children))
+	for _, child := range c.children {
+		if child == nil {
+			continue
+		}
+
+		expr, err := child.compile(dialect, s)
+		if err != nil {
+			return nil, err
+		}
+
+		// An empty condition matches everything: it can be ignored in an
+		// AND, but makes the whole OR match everything.
+		if expr == nil {
+			if c.connector == clause.OrWithSpace {
+				exprs = nil
+				break
+			}
+			continue
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 0 {
+		if c.negated {
+			// NOT (everything) matches nothing
+			return clause.Expr{SQL: "1 = 0"}, nil
+		}
+		return nil, nil
+	}
+
+	expr := joinExprs(c.connector, exprs)
+	if c.negated {
+		return negate(expr), nil
+	}
+	return expr, nil
+}
+
+// conditionMap merges conditions into a single filter map when every
+// condition is a Q, so that backends without QuerySets can run them. It
+// reports false for Or, And and Not groups, and for a lookup repeated with
+// another value, which one map cannot express.
+func conditionMap(conditions []Condition) (map[string]interface{}, bool) {
+	merged := make(map[string]interface{})
+	for _, condition := range conditions {
+		q, ok := condition.(Q)
+		if !ok {
+			return nil, false
+		}
+		for key, value := range q {
+			if _, exists := merged[key]; exists {
+				return nil, false
+			}
+			merged[key] = value
+		}
+	}
+	return merged, true
+}
+
+// FilterQ retrieves the records matching all of the given conditions,
+// which unlike Filter's map may be combined with Or, And and Not:
+//
+//	err := accessor.FilterQ(&articles, gobase.Q{"status": "published"}.Or(gobase.Q{"author": author}))
+//
+// On backends without QuerySets only plain Q conditions are supported.
+func (a *Accessor) FilterQ(models interface{}, conditions ...Condition) error {
+	model, err := a.sliceModel(models)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := a.queryCompiler(); !ok {
+		if filter, ok := conditionMap(conditions); ok {
+			return a.Filter(models, filter)
+		}
+	}
+	return a.Objects(model).Filter(conditions...).All(models)
+}
+
+// ExcludeQ retrieves the records matching none of the given conditions,
+// like Django's exclude(). Records whose nullable columns are NULL are
+// kept. It needs QuerySets.
+func (a *Accessor) ExcludeQ(models interface{}, conditions ...Condition) error {
+	model, err := a.sliceModel(models)
+	if err != nil {
+		return err
+	}
+	return a.Objects(model).Exclude(conditions...).All(models)
+}
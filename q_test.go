@@ -0,0 +1,155 @@
+package gobase
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestQ_Conditions tests composing Q objects with Or, And and Not
+func TestQ_Conditions(t *testing.T) {
+	accessor := setupArticles(t)
+
+	tests := []struct {
+		name     string
+		filter   []Condition
+		exclude  []Condition
+		expected []string
+	}{
+		{
+			name:     "Plain Q",
+			filter:   []Condition{Q{"author": "alice", "status": "published"}},
+			expected: []string{"Go Basics"},
+		},
+		{
+			name:     "Or",
+			filter:   []Condition{Q{"status": "draft"}.Or(Q{"author": "carol"})},
+			expected: []string{"Draft Notes", "Django Tips"},
+		},
+		{
+			name:     "Or with lookups",
+			filter:   []Condition{Q{"views__gte": 50}.Or(Q{"views": 0}, Q{"title__startswith": "Dj"})},
+			expected: []string{"Advanced Go", "Draft Notes", "Django Tips"},
+		},
+		{
+			name:     "Not",
+			filter:   []Condition{Q{"author": "alice"}.Not()},
+			expected: []string{"Advanced Go", "Django Tips"},
+		},
+		{
+			name:     "Not negates the whole group",
+			filter:   []Condition{Q{"author": "alice", "status": "draft"}.Not()},
+			expected: []string{"Go Basics", "Advanced Go", "Django Tips"},
+		},
+		{
+			name: "Nested (A OR B) AND NOT C",
+			filter: []Condition{
+				Q{"status": "published"}.Or(Q{"author": "alice"}).And(Q{"views__gt": 20}.Not()),
+			},
+			expected: []string{"Go Basics", "Draft Notes"},
+		},
+		{
+			name:     "Double negation",
+			filter:   []Condition{Q{"author": "bob"}.Not().Not()},
+			expected: []string{"Advanced Go"},
+		},
+		{
+			name:     "Multiple conditions are ANDed",
+			filter:   []Condition{Q{"status": "published"}, Q{"author": "bob"}.Or(Q{"author": "carol"})},
+			expected: []string{"Advanced Go", "Django Tips"},
+		},
+		{
+			name:     "Empty Q in Or matches everything",
+			filter:   []Condition{Q{"author": "bob"}.Or(Q{})},
+			expected: []string{"Go Basics", "Advanced Go", "Draft Notes", "Django Tips"},
+		},
+		{
+			name:     "Exclude with Or",
+			exclude:  []Condition{Q{"author": "alice"}.Or(Q{"views__gt": 40})},
+			expected: []string{"Django Tips"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			err := accessor.Objects(&Article{}).
+				Filter(tt.filter...).
+				Exclude(tt.exclude...).
+				OrderBy("id").
+				All(&articles)
+			if err != nil {
+				t.Fatalf("All failed: %v", err)
+			}
+
+			if len(articles) != len(tt.expected) {
+				t.Fatalf("Expected %d articles, got %d", len(tt.expected), len(articles))
+			}
+			for i, article := range articles {
+				if article.Title != tt.expected[i] {
+					t.Errorf("Expected %s at position %d, got %s", tt.expected[i], i, article.Title)
+				}
+			}
+		})
+	}
+}
+
+// TestQ_UnknownField tests that nested Q objects validate field names
+func TestQ_UnknownField(t *testing.T) {
+	accessor := setupArticles(t)
+
+	_, err := accessor.Objects(&Article{}).
+		Filter(Q{"status": "published"}.Or(Q{"missing__gt": 1}.Not())).
+		Count()
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("Expected *FieldError, got %v", err)
+	}
+	if fieldErr.Field != "missing" {
+		t.Errorf("Expected field 'missing', got %s", fieldErr.Field)
+	}
+}
+
+// TestAccessor_FilterQ tests filtering and excluding with conditions on the Accessor
+func TestAccessor_FilterQ(t *testing.T) {
+	tests := []struct {
+		name     string
+		accessor *Accessor
+		filter   []Condition
+		exclude  []Condition
+		expected int
+		wantErr  error
+	}{
+		{name: "Filter Or", accessor: setupArticles(t), filter: []Condition{Q{"status": "draft"}.Or(Q{"author": "carol"})}, expected: 2},
+		{name: "Filter Not", accessor: setupArticles(t), filter: []Condition{Q{"author": "alice"}.Not()}, expected: 2},
+		{name: "Exclude", accessor: setupArticles(t), exclude: []Condition{Q{"author": "alice"}.Or(Q{"views__gt": 40})}, expected: 1},
+		{name: "Plain Q on memory", accessor: setupMemoryArticles(t), filter: []Condition{Q{"author": "alice"}, Q{"views__gt": 5}}, expected: 1},
+		{name: "Or on memory", accessor: setupMemoryArticles(t), filter: []Condition{Q{"author": "alice"}.Or(Q{"author": "bob"})}, wantErr: ErrNotSupported},
+		{name: "Exclude on memory", accessor: setupMemoryArticles(t), exclude: []Condition{Q{"author": "alice"}}, wantErr: ErrNotSupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			var err error
+			if tt.exclude != nil {
+				err = tt.accessor.ExcludeQ(&articles, tt.exclude...)
+			} else {
+				err = tt.accessor.FilterQ(&articles, tt.filter...)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if len(articles) != tt.expected {
+				t.Errorf("Expected %d articles, got %d", tt.expected, len(articles))
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package gobase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuerySet is a lazy, chainable query over a single model, modelled after
+// Django's QuerySet. Building a QuerySet never touches the database; the
+// query is only executed when a terminal method such as All, Get, First,
+// Last, Count or Exists is called.
+//
+// Every chaining method returns a new QuerySet, so a QuerySet can be safely
+// reused as the base for several different queries.
+type QuerySet struct {
+	accessor *Accessor
+	model    interface{}
+	filters  []Condition
+	excludes []Condition
+	orders   []string
+	groupBy  []string
+	limit    int
+	offset   int
+	err      error
+
+	// deleted selects whether soft-deleted records are matched
+	deleted DeletedScope
+
+	// forUpdate locks the selected rows until the end of the transaction
+	// on databases that support SELECT ... FOR UPDATE.
+	forUpdate bool
+}
+
+// Objects returns a QuerySet for the given model. The model must embed
+// BaseModel; validation errors are reported by the first terminal call.
+//
+//	var articles []Article
+//	err := accessor.Objects(&Article{}).
+//		Filter(gobase.Q{"status": "published"}).
+//		OrderBy("-created_at").
+//		Limit(10).
+//		All(&articles)
+func (a *Accessor) Objects(model interface{}) *QuerySet {
+	qs := &QuerySet{accessor: a, model: model, limit: -1, deleted: a.deleted}
+	if err := a.ValidateModel(model); err != nil {
+		qs.err = fmt.Errorf("model validation failed: %w", err)
+	}
+	return qs
+}
+
+// clone returns a copy of the QuerySet that can be modified independently.
+func (qs *QuerySet) clone() *QuerySet {
+	c := *qs
+	c.filters = append([]Condition(nil), qs.filters...)
+	c.excludes = append([]Condition(nil), qs.excludes...)
+	c.orders = append([]string(nil), qs.orders...)
+	c.groupBy = append([]string(nil), qs.groupBy...)
+	return &c
+}
+
+// Filter returns a new QuerySet containing only records matching all of the
+// given conditions. Multiple conditions and multiple Filter calls are
+// combined with AND.
+//
+// Condition keys are field names optionally followed by a Django-style
+// lookup, e.g. Q{"views__gte": 10} or Q{"title__icontains": "go"}. Keys
+// without a lookup test for equality. Field names must exist on the model
+// (as a struct field or column name), otherwise the terminal call returns a
+// *FieldError.
+func (qs *QuerySet) Filter(conditions ...Condition) *QuerySet {
+	c := qs.clone()
+	c.filters = append(c.filters, conditions...)
+	return c
+}
+
+// Exclude returns a new QuerySet without the records matching all of the
+// given conditions.
+func (qs *QuerySet) Exclude(conditions ...Condition) *QuerySet {
+	c := qs.clone()
+	if len(conditions) > 0 {
+		c.excludes = append(c.excludes, combineConditions(clause.AndWithSpace, conditions[0], conditions[1:]))
+	}
+	return c
+}
+
+// OrderBy returns a new QuerySet ordered by the given fields. A leading "-"
+// sorts the field in descending order, e.g. OrderBy("-created_at", "title").
+// Fields must exist on the model, otherwise a *FieldError is returned.
+// Calling OrderBy again replaces any previous ordering.
+func (qs *QuerySet) OrderBy(fields ...string) *QuerySet {
+	c := qs.clone()
+	c.orders = append([]string(nil), fields...)
+	return c
+}
+
+// Limit returns a new QuerySet returning at most n records.
+func (qs *QuerySet) Limit(n int) *QuerySet {
+	c := qs.clone()
+	c.limit = n
+	return c
+}
+
+// Offset returns a new QuerySet that skips the first n records.
+func (qs *QuerySet) Offset(n int) *QuerySet {
+	c := qs.clone()
+	c.offset = n
+	return c
+}
+
+// All executes the query and populates the provided slice.
+func (qs *QuerySet) All(models interface{}) error {
+	if models == nil {
+		return errors.New("models cannot be nil")
+	}
+
+	rv := reflect.ValueOf(models)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("models must be a pointer to a slice")
+	}
+
+	db, err := qs.build()
+	if err != nil {
+		return err
+	}
+
+	return translateError(db.Find(models).Error)
+}
+
+// First executes the query and populates model with the first matching
+// record. Without an explicit ordering records are ordered by primary key.
+func (qs *QuerySet) First(model interface{}) error {
+	db, err := qs.build()
+	if err != nil {
+		return err
+	}
+
+	return translateError(db.First(model).Error)
+}
+
+// Last executes the query and populates model with the last matching record,
+// i.e. the first record of the reversed ordering.
+func (qs *QuerySet) Last(model interface{}) error {
+	reversed := qs.clone()
+	reversed.orders = make([]string, len(qs.orders))
+	for i, field := range qs.orders {
+		if strings.HasPrefix(field, "-") {
+			reversed.orders[i] = strings.TrimPrefix(field, "-")
+		} else {
+			reversed.orders[i] = "-" + field
+		}
+	}
+
+	db, err := reversed.build()
+	if err != nil {
+		return err
+	}
+
+	return translateError(db.Last(model).Error)
+}
+
+// Get executes the query and populates model with the single matching
+// record. It returns ErrDoesNotExist when no record matches and
+// ErrMultipleObjectsReturned when more than one does.
+func (qs *QuerySet) Get(model interface{}) error {
+	rv := reflect.ValueOf(model)
+	if model == nil || rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("model must be a non-nil pointer")
+	}
+
+	// Fetching two rows is enough to tell "one" from "several"
+	results := reflect.New(reflect.SliceOf(rv.Elem().Type()))
+	if err := qs.Limit(2).All(results.Interface()); err != nil {
+		return err
+	}
+
+	switch results.Elem().Len() {
+	case 0:
+		return translateError(gorm.ErrRecordNotFound)
+	case 1:
+		rv.Elem().Set(results.Elem().Index(0))
+		return nil
+	default:
+		return ErrMultipleObjectsReturned
+	}
+}
+
+// Count returns the number of records matched by the query. Limit and
+// Offset are taken into account.
+func (qs *QuerySet) Count() (int64, error) {
+	db, err := qs.build()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if qs.limit >= 0 || qs.offset > 0 {
+		// COUNT ignores LIMIT/OFFSET, so count the sliced rows in a subquery
+		subquery := db.Select("1")
+		result := qs.session().Table("(?) AS sliced", subquery).Count(&count)
+		return count, translateError(result.Error)
+	}
+
+	result := db.Count(&count)
+	return count, translateError(result.Error)
+}
+
+// Exists reports whether the query matches at least one record.
+func (qs *QuerySet) Exists() (bool, error) {
+	count, err := qs.Limit(1).Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// session returns a fresh GORM session bound to the QuerySet's connection
+// and context.
+func (qs *QuerySet) session() *gorm.DB {
+	return qs.accessor.db().Session(&gorm.Session{NewDB: true})
+}
+
+// build compiles the QuerySet into a GORM query without executing it.
+func (qs *QuerySet) build() (*gorm.DB, error) {
+	if qs.err != nil {
+		return nil, qs.err
+	}
+
+	// QuerySets compile to SQL run by the backend's QueryCompiler
+	if err := qs.accessor.requireQueryCompiler("QuerySet"); err != nil {
+		return nil, err
+	}
+
+	s, err := qs.accessor.modelSchema(qs.model)
+	if err != nil {
+		return nil, err
+	}
+
+	db := applyDeletedScope(qs.session().Model(qs.model), s, qs.deleted)
+	dialect := db.Dialector.Name()
+
+	for _, condition := range qs.filters {
+		if condition == nil {
+			continue
+		}
+		expr, err := condition.compile(dialect, s)
+		if err != nil {
+			return nil, err
+		}
+		if expr != nil {
+			db = db.Where(expr)
+		}
+	}
+
+	for _, condition := range qs.excludes {
+		expr, err := condition.compile(dialect, s)
+		if err != nil {
+			return nil, err
+		}
+		if expr != nil {
+			db = db.Where(negate(expr))
+		}
+	}
+
+	for _, field := range qs.orders {
+		column, err := resolveField(s, strings.TrimPrefix(field, "-"))
+		if err != nil {
+			return nil, err
+		}
+		db = db.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: column},
+			Desc:   strings.HasPrefix(field, "-"),
+		})
+	}
+
+	if qs.limit >= 0 {
+		db = db.Limit(qs.limit)
+	}
+	if qs.offset > 0 {
+		db = db.Offset(qs.offset)
+	}
+	if qs.forUpdate && dialect == dialectPostgres {
+		db = db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+	}
+
+	return db, nil
+}
+
+// negate returns the negation of expr. NOT (author = 'bob') is NULL
+// rather than true where author is NULL, so, like Django's exclude(),
+// lookups on nullable columns are negated as
+// NOT (author = 'bob') OR author IS NULL. Groups are negated by De
+// Morgan's laws to reach those lookups.
+func negate(expr clause.Expression) clause.Expression {
+	switch e := expr.(type) {
+	case nullableExpr:
+		return joinedExpr{
+			connector: clause.OrWithSpace,
+			exprs:     []clause.Expression{notExpr{expr: e.expr}, clause.Expr{SQL: "? IS NULL", Vars: []interface{}{e.column}}},
+		}
+	case joinedExpr:
+		connector := clause.OrWithSpace
+		if e.connector == clause.OrWithSpace {
+			connector = clause.AndWithSpace
+		}
+		exprs := make([]clause.Expression, len(e.exprs))
+		for i, child := range e.exprs {
+			exprs[i] = negate(child)
+		}
+		return joinedExpr{connector: connector, exprs: exprs}
+	case notExpr:
+		return e.expr
+	}
+	return notExpr{expr: expr}
+}
+
+// nullableExpr is a lookup on a nullable column, which evaluates to NULL
+// where the column is NULL.
+type nullableExpr struct {
+	expr   clause.Expression
+	column clause.Column
+}
+
+// Build writes the lookup to the builder.
+func (n nullableExpr) Build(builder clause.Builder) {
+	n.expr.Build(builder)
+}
+
+// notExpr negates an expression as a whole. GORM's clause.Not negates each
+// operand of an AND individually, which is not what Exclude means.
+type notExpr struct {
+	expr clause.Expression
+}
+
+// Build writes NOT (expr) to the builder.
+func (n notExpr) Build(builder clause.Builder) {
+	builder.WriteString("NOT (")
+	n.expr.Build(builder)
+	builder.WriteByte(')')
+}
+
+// joinedExpr joins expressions with AND or OR inside parentheses. It is
+// used instead of clause.And/clause.Or, whose builders rewrite connectors
+// for single-element groups.
+type joinedExpr struct {
+	connector string
+	exprs     []clause.Expression
+}
+
+// joinExprs joins exprs with connector, returning a lone expression as is.
+func joinExprs(connector string, exprs []clause.Expression) clause.Expression {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	return joinedExpr{connector: connector, exprs: exprs}
+}
+
+// Build writes (expr1 connector expr2 ...) to the builder.
+func (j joinedExpr) Build(builder clause.Builder) {
+	builder.WriteByte('(')
+	for i, expr := range j.exprs {
+		if i > 0 {
+			builder.WriteString(j.connector)
+		}
+		expr.Build(builder)
+	}
+	builder.WriteByte(')')
+}
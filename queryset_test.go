@@ -0,0 +1,370 @@
+package gobase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Article is a richer model used for query tests
+type Article struct {
+	BaseModel
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Status string `json:"status"`
+	Views  int    `json:"views"`
+}
+
+// setupArticles creates a migrated test database with a few articles
+func setupArticles(t *testing.T) *Accessor {
+	accessor := NewAccessor(setupTestDB(t))
+
+	err := accessor.Migrate(&Article{})
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	articles := []*Article{
+		{Title: "Go Basics", Author: "alice", Status: "published", Views: 10},
+		{Title: "Advanced Go", Author: "bob", Status: "published", Views: 50},
+		{Title: "Draft Notes", Author: "alice", Status: "draft", Views: 0},
+		{Title: "Django Tips", Author: "carol", Status: "published", Views: 30},
+	}
+
+	for _, article := range articles {
+		if err := accessor.Create(article); err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+
+	return accessor
+}
+
+// TestQuerySet_FilterExclude tests chaining Filter and Exclude
+func TestQuerySet_FilterExclude(t *testing.T) {
+	accessor := setupArticles(t)
+
+	var articles []Article
+	err := accessor.Objects(&Article{}).
+		Filter(Q{"status": "published"}).
+		Exclude(Q{"author": "bob"}).
+		OrderBy("title").
+		All(&articles)
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	if len(articles) != 2 {
+		t.Fatalf("Expected 2 articles, got %d", len(articles))
+	}
+
+	if articles[0].Title != "Django Tips" || articles[1].Title != "Go Basics" {
+		t.Errorf("Unexpected order: %s, %s", articles[0].Title, articles[1].Title)
+	}
+}
+
+// Ticket is a model with nullable columns for NULL handling tests
+type Ticket struct {
+	BaseModel
+	Title    string  `json:"title"`
+	Assignee *string `json:"assignee"`
+	Priority *int    `json:"priority"`
+}
+
+// TestQuerySet_ExcludeNull tests that negated lookups keep rows with NULL columns
+func TestQuerySet_ExcludeNull(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&Ticket{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	bob, alice, high := "bob", "alice", 1
+	tickets := []*Ticket{
+		{Title: "Assigned", Assignee: &bob, Priority: &high},
+		{Title: "Unprioritized", Assignee: &alice},
+		{Title: "Triage"},
+	}
+	for _, ticket := range tickets {
+		if err := accessor.Create(ticket); err != nil {
+			t.Fatalf("Failed to create ticket: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filter   []Condition
+		exclude  []Condition
+		expected []string
+	}{
+		{
+			name:     "Exclude",
+			exclude:  []Condition{Q{"assignee": "bob"}},
+			expected: []string{"Unprioritized", "Triage"},
+		},
+		{
+			name:     "Exclude group",
+			exclude:  []Condition{Q{"assignee": "bob", "priority__gt": 0}},
+			expected: []string{"Unprioritized", "Triage"},
+		},
+		{
+			name:     "Exclude isnull",
+			exclude:  []Condition{Q{"assignee__isnull": true}},
+			expected: []string{"Assigned", "Unprioritized"},
+		},
+		{
+			name:     "Not",
+			filter:   []Condition{Q{"assignee": "bob"}.Not()},
+			expected: []string{"Unprioritized", "Triage"},
+		},
+		{
+			name:     "Not Or",
+			filter:   []Condition{Q{"priority__gt": 0}.Or(Q{"assignee": "alice"}).Not()},
+			expected: []string{"Triage"},
+		},
+		{
+			name:     "Double negation",
+			filter:   []Condition{Q{"assignee": "bob"}.Not().Not()},
+			expected: []string{"Assigned"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results []Ticket
+			err := accessor.Objects(&Ticket{}).
+				Filter(tt.filter...).
+				Exclude(tt.exclude...).
+				OrderBy("id").
+				All(&results)
+			if err != nil {
+				t.Fatalf("All failed: %v", err)
+			}
+
+			var titles []string
+			for _, ticket := range results {
+				titles = append(titles, ticket.Title)
+			}
+			if strings.Join(titles, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, titles)
+			}
+		})
+	}
+}
+
+// TestQuerySet_Lazy tests that chaining does not modify the base QuerySet
+func TestQuerySet_Lazy(t *testing.T) {
+	accessor := setupArticles(t)
+
+	base := accessor.Objects(&Article{})
+	published := base.Filter(Q{"status": "published"})
+
+	total, err := base.Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if total != 4 {
+		t.Errorf("Expected base count 4, got %d", total)
+	}
+
+	count, err := published.Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected published count 3, got %d", count)
+	}
+}
+
+// TestQuerySet_FirstLast tests First and Last with and without ordering
+func TestQuerySet_FirstLast(t *testing.T) {
+	accessor := setupArticles(t)
+
+	tests := []struct {
+		name      string
+		qs        *QuerySet
+		wantFirst string
+		wantLast  string
+	}{
+		{
+			name:      "Default primary key ordering",
+			qs:        accessor.Objects(&Article{}),
+			wantFirst: "Go Basics",
+			wantLast:  "Django Tips",
+		},
+		{
+			name:      "Descending views",
+			qs:        accessor.Objects(&Article{}).OrderBy("-views"),
+			wantFirst: "Advanced Go",
+			wantLast:  "Draft Notes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var first, last Article
+			if err := tt.qs.First(&first); err != nil {
+				t.Fatalf("First failed: %v", err)
+			}
+			if err := tt.qs.Last(&last); err != nil {
+				t.Fatalf("Last failed: %v", err)
+			}
+
+			if first.Title != tt.wantFirst {
+				t.Errorf("Expected first %s, got %s", tt.wantFirst, first.Title)
+			}
+			if last.Title != tt.wantLast {
+				t.Errorf("Expected last %s, got %s", tt.wantLast, last.Title)
+			}
+		})
+	}
+}
+
+// TestQuerySet_LimitOffset tests slicing and counting sliced results
+func TestQuerySet_LimitOffset(t *testing.T) {
+	accessor := setupArticles(t)
+
+	qs := accessor.Objects(&Article{}).OrderBy("id").Offset(1).Limit(2)
+
+	var articles []Article
+	if err := qs.All(&articles); err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	if len(articles) != 2 || articles[0].Title != "Advanced Go" {
+		t.Errorf("Unexpected slice result: %+v", articles)
+	}
+
+	count, err := qs.Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected sliced count 2, got %d", count)
+	}
+}
+
+// TestQuerySet_Exists tests Exists and model validation
+func TestQuerySet_Exists(t *testing.T) {
+	accessor := setupArticles(t)
+
+	exists, err := accessor.Objects(&Article{}).Filter(Q{"author": "carol"}).Exists()
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if !exists {
+		t.Error("Expected carol's article to exist")
+	}
+
+	exists, err = accessor.Objects(&Article{}).Filter(Q{"author": "dave"}).Exists()
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if exists {
+		t.Error("Expected no article by dave")
+	}
+
+	_, err = accessor.Objects(&struct{ Name string }{}).Exists()
+	if err == nil {
+		t.Error("Expected validation error for model without BaseModel")
+	}
+}
+
+// TestQuerySet_Transaction tests that QuerySets work inside transactions
+func TestQuerySet_Transaction(t *testing.T) {
+	accessor := setupArticles(t)
+
+	err := accessor.Transaction(func(tx *Accessor) error {
+		if err := tx.Create(&Article{Title: "In Tx", Status: "draft"}); err != nil {
+			return err
+		}
+
+		count, err := tx.Objects(&Article{}).Filter(Q{"status": "draft"}).Count()
+		if err != nil {
+			return err
+		}
+		if count != 2 {
+			t.Errorf("Expected 2 drafts inside transaction, got %d", count)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+}
+
+// TestQuerySet_UnknownField tests that unknown field names are rejected
+// before any SQL is built from them
+func TestQuerySet_UnknownField(t *testing.T) {
+	accessor := setupArticles(t)
+
+	injection := "status = 'draft' OR 1=1; --"
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{
+			name: "Filter key",
+			run: func() error {
+				var articles []Article
+				return accessor.Filter(&articles, map[string]interface{}{injection: "x"})
+			},
+		},
+		{
+			name: "Exclude key",
+			run: func() error {
+				_, err := accessor.Objects(&Article{}).Exclude(Q{injection + "__gt": 1}).Count()
+				return err
+			},
+		},
+		{
+			name: "OrderBy field",
+			run: func() error {
+				var articles []Article
+				return accessor.Objects(&Article{}).OrderBy("-" + injection).All(&articles)
+			},
+		},
+		{
+			name: "CountFilter key",
+			run: func() error {
+				_, err := accessor.CountFilter(&Article{}, map[string]interface{}{injection: "x"})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("Expected *FieldError, got %v", err)
+			}
+			if fieldErr.Field != injection || fieldErr.Model != "Article" {
+				t.Errorf("Unexpected field error: %+v", fieldErr)
+			}
+		})
+	}
+}
+
+// TestQuerySet_FieldNames tests that struct field names resolve to columns
+func TestQuerySet_FieldNames(t *testing.T) {
+	accessor := setupArticles(t)
+
+	count, err := accessor.CountFilter(&Article{}, map[string]interface{}{"Author": "alice", "Views__gt": 5})
+	if err != nil {
+		t.Fatalf("CountFilter failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 article, got %d", count)
+	}
+
+	var first Article
+	if err := accessor.Objects(&Article{}).OrderBy("-Views").First(&first); err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if first.Title != "Advanced Go" {
+		t.Errorf("Expected most viewed article, got %s", first.Title)
+	}
+}
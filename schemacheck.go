@@ -0,0 +1,186 @@
+package gobase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of schema differences reported by CheckSchema.
+const (
+	// DriftMissingTable is a model without a table.
+	DriftMissingTable = "missing_table"
+	// DriftMissingColumn is a model field without a column.
+	DriftMissingColumn = "missing_column"
+	// DriftExtraColumn is a column without a model field.
+	DriftExtraColumn = "extra_column"
+	// DriftTypeMismatch is a column whose type differs from its field's.
+	DriftTypeMismatch = "type_mismatch"
+	// DriftMissingIndex is an index declared in gorm tags that does not
+	// exist.
+	DriftMissingIndex = "missing_index"
+)
+
+// SchemaDifference is a difference between a model and its table.
+// Expected and Actual hold the column types of type mismatches, and the
+// expected type of missing columns.
+type SchemaDifference struct {
+	Kind     string `json:"kind"`
+	Model    string `json:"model"`
+	Table    string `json:"table"`
+	Column   string `json:"column,omitempty"`
+	Index    string `json:"index,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// String describes the difference for humans.
+func (d SchemaDifference) String() string {
+	switch d.Kind {
+	case DriftMissingTable:
+		return fmt.Sprintf("%s: table %s is missing", d.Model, d.Table)
+	case DriftMissingColumn:
+		return fmt.Sprintf("%s: column %s.%s (%s) is missing", d.Model, d.Table, d.Column, d.Expected)
+	case DriftExtraColumn:
+		return fmt.Sprintf("%s: column %s.%s (%s) has no field", d.Model, d.Table, d.Column, d.Actual)
+	case DriftTypeMismatch:
+		return fmt.Sprintf("%s: column %s.%s is %s, expected %s", d.Model, d.Table, d.Column, d.Actual, d.Expected)
+	case DriftMissingIndex:
+		return fmt.Sprintf("%s: index %s on %s is missing", d.Model, d.Index, d.Table)
+	}
+	return fmt.Sprintf("%s: %s on %s", d.Model, d.Kind, d.Table)
+}
+
+// SchemaReport is the result of CheckSchema. It marshals to JSON for
+// tooling.
+type SchemaReport struct {
+	Differences []SchemaDifference `json:"differences"`
+}
+
+// HasDrift reports whether the database differs from the models.
+func (r *SchemaReport) HasDrift() bool {
+	return len(r.Differences) > 0
+}
+
+// String renders the report for humans, one difference per line.
+func (r *SchemaReport) String() string {
+	if !r.HasDrift() {
+		return "No schema drift detected\n"
+	}
+
+	var report strings.Builder
+	noun := "differences"
+	if len(r.Differences) == 1 {
+		noun = "difference"
+	}
+	fmt.Fprintf(&report, "Schema drift detected (%d %s):\n", len(r.Differences), noun)
+	for _, difference := range r.Differences {
+		fmt.Fprintf(&report, "  %s\n", difference)
+	}
+	return report.String()
+}
+
+// CheckSchema compares the live database with the given models, or the
+// registered models when none are given, and reports missing tables,
+// missing and extra columns, column type mismatches and missing indexes,
+// e.g. to fail CI when migrations were not applied or a table was altered
+// by hand. Column types are compared the way GORM's AutoMigrate does, by
+// the prefix of their type name; sizes, precision, nullability, defaults
+// and unique constraints are not compared, so Migrate may still alter a
+// model reported as in sync.
+//
+//	report, err := accessor.CheckSchema()
+//	if err == nil && report.HasDrift() {
+//		fmt.Print(report)
+//	}
+func (a *Accessor) CheckSchema(models ...interface{}) (*SchemaReport, error) {
+	// Only supported by Migrator backends
+	if err := a.requireMigrator("CheckSchema"); err != nil {
+		return nil, err
+	}
+
+	report := &SchemaReport{Differences: []SchemaDifference{}}
+	for _, model := range migrationModels(models) {
+		differences, err := a.checkModelSchema(model)
+		if err != nil {
+			return nil, err
+		}
+		report.Differences = append(report.Differences, differences...)
+	}
+	return report, nil
+}
+
+// checkModelSchema compares a model with its table.
+func (a *Accessor) checkModelSchema(model interface{}) ([]SchemaDifference, error) {
+	s, err := a.modelSchema(model)
+	if err != nil {
+		return nil, err
+	}
+	m := a.introspectionDB().Migrator()
+	difference := func(kind string) SchemaDifference {
+		return SchemaDifference{Kind: kind, Model: s.Name, Table: s.Table}
+	}
+
+	if !m.HasTable(s.Table) {
+		return []SchemaDifference{difference(DriftMissingTable)}, nil
+	}
+
+	columns, err := a.columnTypes(s.Table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect columns of %s: %w", s.Table, translateError(err))
+	}
+
+	var differences []SchemaDifference
+	existing := map[string]bool{}
+	for _, column := range columns {
+		existing[column.Name()] = true
+		field := s.LookUpField(column.Name())
+		if field == nil || field.DBName != column.Name() || field.IgnoreMigration {
+			d := difference(DriftExtraColumn)
+			d.Column, d.Actual = column.Name(), strings.ToLower(column.DatabaseTypeName())
+			differences = append(differences, d)
+			continue
+		}
+
+		expected := strings.TrimSpace(strings.ToLower(m.FullDataTypeOf(field).SQL))
+		actual := strings.ToLower(column.DatabaseTypeName())
+		if !field.PrimaryKey && !sameColumnType(m.GetTypeAliases(actual), expected, actual) {
+			d := difference(DriftTypeMismatch)
+			d.Column, d.Expected, d.Actual = column.Name(), a.migrationDB().Dialector.DataTypeOf(field), actual
+			differences = append(differences, d)
+		}
+	}
+
+	for _, name := range s.DBNames {
+		field := s.FieldsByDBName[name]
+		if existing[name] || field.IgnoreMigration {
+			continue
+		}
+		d := difference(DriftMissingColumn)
+		d.Column, d.Expected = name, a.migrationDB().Dialector.DataTypeOf(field)
+		differences = append(differences, d)
+	}
+
+	for _, index := range s.ParseIndexes() {
+		if !m.HasIndex(model, index.Name) {
+			d := difference(DriftMissingIndex)
+			d.Index = index.Name
+			differences = append(differences, d)
+		}
+	}
+	return differences, nil
+}
+
+// sameColumnType reports whether a column of the actual type matches the
+// expected full data type, following GORM's AutoMigrate: the expected type
+// may start with the actual type or one of its aliases.
+func sameColumnType(aliases []string, expected, actual string) bool {
+	if expected == actual || strings.HasPrefix(expected, actual) {
+		return true
+	}
+	for _, alias := range aliases {
+		if strings.HasPrefix(expected, alias) {
+			return true
+		}
+	}
+	return false
+}
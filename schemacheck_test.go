@@ -0,0 +1,111 @@
+package gobase
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestAccessor_CheckSchema tests detecting differences between models and tables
+func TestAccessor_CheckSchema(t *testing.T) {
+	accessor := NewAccessor(setupTestDB(t))
+	if err := accessor.Migrate(&Article{}, &User{}, &Publisher{}, &Book{}, &InventoryItemV1{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if err := accessor.connection.GormDB.Exec("CREATE TABLE legacy_items (id integer PRIMARY KEY, quantity text)").Error; err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		models   []interface{}
+		expected []SchemaDifference
+	}{
+		{
+			name:   "In sync",
+			models: []interface{}{&Article{}, &User{}, &Publisher{}, &Book{}, &InventoryItemV1{}},
+		},
+		{
+			name:     "Missing table",
+			models:   []interface{}{&Chapter{}},
+			expected: []SchemaDifference{{Kind: DriftMissingTable, Model: "Chapter", Table: "chapters"}},
+		},
+		{
+			name:   "Columns and indexes",
+			models: []interface{}{&InventoryItemV2{}},
+			expected: []SchemaDifference{
+				{Kind: DriftExtraColumn, Model: "InventoryItemV2", Table: "inventory_items", Column: "notes", Actual: "text"},
+				{Kind: DriftMissingColumn, Model: "InventoryItemV2", Table: "inventory_items", Column: "sku", Expected: "text"},
+				{Kind: DriftMissingColumn, Model: "InventoryItemV2", Table: "inventory_items", Column: "quantity", Expected: "integer"},
+				{Kind: DriftMissingIndex, Model: "InventoryItemV2", Table: "inventory_items", Index: "idx_inventory_items_sku"},
+			},
+		},
+		{
+			name:   "Type mismatch",
+			models: []interface{}{&LegacyItem{}},
+			expected: []SchemaDifference{
+				{Kind: DriftTypeMismatch, Model: "LegacyItem", Table: "legacy_items", Column: "quantity", Expected: "integer", Actual: "text"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := accessor.CheckSchema(tt.models...)
+			if err != nil {
+				t.Fatalf("CheckSchema failed: %v", err)
+			}
+			if report.HasDrift() != (len(tt.expected) > 0) {
+				t.Errorf("Expected drift %v, got report:\n%s", len(tt.expected) > 0, report)
+			}
+			if len(report.Differences) != len(tt.expected) {
+				t.Fatalf("Expected %d differences, got %d:\n%s", len(tt.expected), len(report.Differences), report)
+			}
+			for i, expected := range tt.expected {
+				if report.Differences[i] != expected {
+					t.Errorf("Expected difference %+v, got %+v", expected, report.Differences[i])
+				}
+			}
+		})
+	}
+}
+
+// LegacyItem maps a table whose quantity column was created with another type
+type LegacyItem struct {
+	ID       uint `gorm:"primarykey"`
+	Quantity int
+}
+
+// TestSchemaReport_Output tests the human and JSON renderings of a report
+func TestSchemaReport_Output(t *testing.T) {
+	report := &SchemaReport{Differences: []SchemaDifference{}}
+	if report.String() != "No schema drift detected\n" {
+		t.Errorf("Unexpected report %q", report.String())
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"differences":[]}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	report.Differences = append(report.Differences,
+		SchemaDifference{Kind: DriftTypeMismatch, Model: "Article", Table: "articles", Column: "views", Expected: "integer", Actual: "text"},
+		SchemaDifference{Kind: DriftMissingIndex, Model: "Article", Table: "articles", Index: "idx_articles_title"},
+	)
+	expected := "Schema drift detected (2 differences):\n" +
+		"  Article: column articles.views is text, expected integer\n" +
+		"  Article: index idx_articles_title on articles is missing\n"
+	if report.String() != expected {
+		t.Errorf("Expected report:\n%s\ngot:\n%s", expected, report)
+	}
+
+	data, err = json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `{"kind":"missing_index","model":"Article","table":"articles","index":"idx_articles_title"}`) {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
@@ -0,0 +1,177 @@
+package gobase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DeletedScope selects which records reads see with respect to soft
+// deletion through BaseModel.DeletedAt.
+type DeletedScope int
+
+const (
+	// ExcludeDeleted hides soft-deleted records (the default).
+	ExcludeDeleted DeletedScope = iota
+	// IncludeDeleted returns live and soft-deleted records.
+	IncludeDeleted
+	// OnlyDeleted returns soft-deleted records only.
+	OnlyDeleted
+)
+
+// deletedAtType is the type of BaseModel.DeletedAt.
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// WithDeleted returns a copy of the Accessor whose reads (Get, All, Filter,
+// Count, FindWhere and QuerySets) include soft-deleted records. Writes are
+// unaffected; Delete still soft-deletes.
+//
+//	err := accessor.WithDeleted().Get(article, id)
+func (a *Accessor) WithDeleted() *Accessor {
+	scoped := *a
+	scoped.deleted = IncludeDeleted
+	return &scoped
+}
+
+// OnlyDeleted returns a copy of the Accessor whose reads only return
+// soft-deleted records, e.g. to list the contents of a trash view.
+func (a *Accessor) OnlyDeleted() *Accessor {
+	scoped := *a
+	scoped.deleted = OnlyDeleted
+	return &scoped
+}
+
+// WithDeleted returns a new QuerySet that includes soft-deleted records.
+func (qs *QuerySet) WithDeleted() *QuerySet {
+	c := qs.clone()
+	c.deleted = IncludeDeleted
+	return c
+}
+
+// OnlyDeleted returns a new QuerySet containing only soft-deleted records.
+func (qs *QuerySet) OnlyDeleted() *QuerySet {
+	c := qs.clone()
+	c.deleted = OnlyDeleted
+	return c
+}
+
+// Restore undeletes a soft-deleted record by clearing its DeletedAt. It
+// returns ErrDoesNotExist if the record does not exist at all.
+func (a *Accessor) Restore(model interface{}) error {
+	if err := a.ValidateModel(model); err != nil {
+		return fmt.Errorf("model validation failed: %w", err)
+	}
+
+	// Only supported by QueryCompiler backends
+	if err := a.requireQueryCompiler("Restore"); err != nil {
+		return err
+	}
+
+	s, err := a.modelSchema(model)
+	if err != nil {
+		return err
+	}
+	field := deletedAtField(s)
+	if field == nil {
+		return fmt.Errorf("model '%s' does not support soft deletion", s.Name)
+	}
+
+	result := a.db().Unscoped().Model(model).Update(field.DBName, nil)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound)
+	}
+
+	return field.Set(a.Context(), reflect.ValueOf(model), gorm.DeletedAt{})
+}
+
+// HardDelete permanently removes the record from the database, whether or
+// not it has been soft-deleted. The on-delete actions of the model's
+// associations are applied, including to soft-deleted related records.
+func (a *Accessor) HardDelete(model interface{}) error {
+	if err := a.ValidateModel(model); err != nil {
+		return fmt.Errorf("model validation failed: %w", err)
+	}
+
+	// Only supported by QueryCompiler backends
+	if err := a.requireQueryCompiler("HardDelete"); err != nil {
+		return err
+	}
+
+	return a.deleteModel(model, true)
+}
+
+// PurgeDeleted permanently removes the model's records that were
+// soft-deleted more than olderThan ago and returns the number removed, for
+// use in retention jobs. A zero olderThan purges every soft-deleted record.
+//
+//	purged, err := accessor.PurgeDeleted(&Article{}, 30*24*time.Hour)
+func (a *Accessor) PurgeDeleted(model interface{}, olderThan time.Duration) (int64, error) {
+	if olderThan < 0 {
+		return 0, errors.New("olderThan cannot be negative")
+	}
+	if err := a.requireQueryCompiler("PurgeDeleted"); err != nil {
+		return 0, err
+	}
+
+	cutoff := a.db().NowFunc().Add(-olderThan)
+	return a.Objects(model).OnlyDeleted().Filter(Q{"deleted_at__lte": cutoff}).HardDelete()
+}
+
+// HardDelete permanently removes every record matched by the query and
+// returns the number removed. Like Delete, it honours the QuerySet's
+// soft-delete scope: by default only live records are removed, while
+// OnlyDeleted().HardDelete() empties the trash. The on-delete actions of
+// the model's associations are applied. Model hooks are not run.
+func (qs *QuerySet) HardDelete() (int64, error) {
+	return qs.deleteQuerySet(true)
+}
+
+// applyDeletedScope adjusts a query on a model with the given schema to the
+// soft-delete scope. GORM hides soft-deleted records by default.
+func applyDeletedScope(db *gorm.DB, s *schema.Schema, scope DeletedScope) *gorm.DB {
+	switch scope {
+	case IncludeDeleted:
+		return db.Unscoped()
+	case OnlyDeleted:
+		return db.Unscoped().Where(deletedAtCondition(s, true))
+	default:
+		return db
+	}
+}
+
+// deletedAtCondition matches soft-deleted records, or live records when
+// deleted is false. Models without a DeletedAt field have no soft-deleted
+// records.
+func deletedAtCondition(s *schema.Schema, deleted bool) clause.Expression {
+	field := deletedAtField(s)
+	if field == nil {
+		if deleted {
+			return clause.Expr{SQL: "1 = 0"}
+		}
+		return clause.Expr{SQL: "1 = 1"}
+	}
+
+	sql := "? IS NULL"
+	if deleted {
+		sql = "? IS NOT NULL"
+	}
+	return clause.Expr{SQL: sql, Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: field.DBName}}}
+}
+
+// deletedAtField returns the model's soft-delete field, if any.
+func deletedAtField(s *schema.Schema) *schema.Field {
+	for _, field := range s.Fields {
+		if field.FieldType == deletedAtType && field.DBName != "" {
+			return field
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package gobase
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// setupDeletedArticles soft-deletes two of the seeded articles
+func setupDeletedArticles(t *testing.T) (*Accessor, map[string]*Article) {
+	accessor := setupArticles(t)
+
+	deleted := map[string]*Article{}
+	for _, title := range []string{"Advanced Go", "Draft Notes"} {
+		article := &Article{}
+		if err := accessor.Objects(&Article{}).Filter(Q{"title": title}).Get(article); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if err := accessor.Delete(article); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		deleted[title] = article
+	}
+
+	return accessor, deleted
+}
+
+// TestAccessor_DeletedScopes tests WithDeleted and OnlyDeleted on reads
+func TestAccessor_DeletedScopes(t *testing.T) {
+	accessor, deleted := setupDeletedArticles(t)
+
+	tests := []struct {
+		name          string
+		accessor      *Accessor
+		expectedCount int64
+		expectedGet   bool
+	}{
+		{name: "Default", accessor: accessor, expectedCount: 2},
+		{name: "WithDeleted", accessor: accessor.WithDeleted(), expectedCount: 4, expectedGet: true},
+		{name: "OnlyDeleted", accessor: accessor.OnlyDeleted(), expectedCount: 2, expectedGet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var articles []Article
+			if err := tt.accessor.All(&articles); err != nil {
+				t.Fatalf("All failed: %v", err)
+			}
+			if int64(len(articles)) != tt.expectedCount {
+				t.Errorf("Expected %d articles from All, got %d", tt.expectedCount, len(articles))
+			}
+
+			count, err := tt.accessor.Count(&Article{}, "views >= ?", 0)
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			if count != tt.expectedCount {
+				t.Errorf("Expected count %d, got %d", tt.expectedCount, count)
+			}
+
+			count, err = tt.accessor.CountFilter(&Article{}, map[string]interface{}{"views__gte": 0})
+			if err != nil {
+				t.Fatalf("CountFilter failed: %v", err)
+			}
+			if count != tt.expectedCount {
+				t.Errorf("Expected filtered count %d, got %d", tt.expectedCount, count)
+			}
+
+			var found []Article
+			if err := tt.accessor.FindWhere(&found, "views >= ?", 0); err != nil {
+				t.Fatalf("FindWhere failed: %v", err)
+			}
+			if int64(len(found)) != tt.expectedCount {
+				t.Errorf("Expected %d articles from FindWhere, got %d", tt.expectedCount, len(found))
+			}
+
+			err = tt.accessor.Get(&Article{}, deleted["Draft Notes"].ID)
+			if tt.expectedGet && err != nil {
+				t.Errorf("Expected Get to find the deleted article, got %v", err)
+			}
+			if !tt.expectedGet && !errors.Is(err, ErrDoesNotExist) {
+				t.Errorf("Expected ErrDoesNotExist, got %v", err)
+			}
+		})
+	}
+
+	var trashed []Article
+	if err := accessor.OnlyDeleted().Filter(&trashed, map[string]interface{}{"author": "alice"}); err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].Title != "Draft Notes" {
+		t.Errorf("Expected only Draft Notes, got %v", trashed)
+	}
+}
+
+// TestQuerySet_DeletedScopes tests soft-delete scopes on QuerySets
+func TestQuerySet_DeletedScopes(t *testing.T) {
+	accessor, _ := setupDeletedArticles(t)
+
+	count, err := accessor.Objects(&Article{}).WithDeleted().Filter(Q{"author": "alice"}).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 articles by alice, got %d", count)
+	}
+
+	result, err := accessor.Objects(&Article{}).OnlyDeleted().Aggregate(map[string]Aggregate{"total": Sum("views")})
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if result["total"] != int64(50) {
+		t.Errorf("Expected 50 views in the trash, got %v", result["total"])
+	}
+
+	// Scopes set on the Accessor carry over to QuerySets
+	count, err = accessor.OnlyDeleted().Objects(&Article{}).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 deleted articles, got %d", count)
+	}
+
+	// Delete never touches records that are already soft-deleted
+	deletedCount, err := accessor.Objects(&Article{}).WithDeleted().Filter(Q{"author": "alice"}).Delete()
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if deletedCount != 1 {
+		t.Errorf("Expected 1 live article to be deleted, got %d", deletedCount)
+	}
+}
+
+// TestAccessor_Restore tests undeleting soft-deleted records
+func TestAccessor_Restore(t *testing.T) {
+	accessor, deleted := setupDeletedArticles(t)
+
+	article := deleted["Advanced Go"]
+	if err := accessor.Restore(article); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if article.DeletedAt.Valid {
+		t.Error("Expected DeletedAt to be cleared on the record")
+	}
+
+	restored := &Article{}
+	if err := accessor.Get(restored, article.ID); err != nil {
+		t.Fatalf("Expected restored article to be visible, got %v", err)
+	}
+	if restored.Title != "Advanced Go" {
+		t.Errorf("Expected Advanced Go, got %s", restored.Title)
+	}
+
+	missing := &Article{}
+	missing.ID = 999
+	if err := accessor.Restore(missing); !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Expected ErrDoesNotExist, got %v", err)
+	}
+	if err := accessor.Restore(&NotAModel{}); err == nil {
+		t.Error("Expected validation error")
+	}
+}
+
+// TestAccessor_HardDelete tests permanent deletion
+func TestAccessor_HardDelete(t *testing.T) {
+	accessor, deleted := setupDeletedArticles(t)
+
+	// Soft-deleted records can be removed permanently
+	if err := accessor.HardDelete(deleted["Draft Notes"]); err != nil {
+		t.Fatalf("HardDelete failed: %v", err)
+	}
+
+	// So can live ones
+	live := &Article{}
+	if err := accessor.Objects(&Article{}).Filter(Q{"title": "Go Basics"}).Get(live); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := accessor.HardDelete(live); err != nil {
+		t.Fatalf("HardDelete failed: %v", err)
+	}
+
+	var remaining []Article
+	if err := accessor.WithDeleted().All(&remaining); err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("Expected 2 remaining articles, got %d", len(remaining))
+	}
+
+	// QuerySet.HardDelete honours the soft-delete scope
+	removed, err := accessor.Objects(&Article{}).HardDelete()
+	if err != nil {
+		t.Fatalf("HardDelete failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 live article to be removed, got %d", removed)
+	}
+
+	count, err := accessor.OnlyDeleted().Objects(&Article{}).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected the deleted article to remain, got %d", count)
+	}
+}
+
+// TestAccessor_PurgeDeleted tests retention-based purging
+func TestAccessor_PurgeDeleted(t *testing.T) {
+	accessor, deleted := setupDeletedArticles(t)
+
+	// Backdate one deletion
+	old := time.Now().Add(-48 * time.Hour)
+	err := accessor.connection.GormDB.Unscoped().Model(&Article{}).
+		Where("id = ?", deleted["Draft Notes"].ID).
+		Update("deleted_at", old).Error
+	if err != nil {
+		t.Fatalf("Failed to backdate deletion: %v", err)
+	}
+
+	purged, err := accessor.PurgeDeleted(&Article{}, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("PurgeDeleted failed: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 article purged, got %d", purged)
+	}
+
+	if err := accessor.WithDeleted().Get(&Article{}, deleted["Draft Notes"].ID); !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Expected purged article to be gone, got %v", err)
+	}
+	if err := accessor.WithDeleted().Get(&Article{}, deleted["Advanced Go"].ID); err != nil {
+		t.Errorf("Expected recently deleted article to remain, got %v", err)
+	}
+
+	purged, err = For[Article](accessor).PurgeDeleted(0)
+	if err != nil {
+		t.Fatalf("PurgeDeleted failed: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 article purged, got %d", purged)
+	}
+
+	count, err := accessor.Objects(&Article{}).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected live articles to be untouched, got %d", count)
+	}
+
+	if _, err := accessor.PurgeDeleted(&Article{}, -time.Hour); err == nil {
+		t.Error("Expected error for negative retention")
+	}
+}
+
+// TestManager_DeletedScopes tests the typed soft-delete helpers
+func TestManager_DeletedScopes(t *testing.T) {
+	accessor, deleted := setupDeletedArticles(t)
+	articles := For[Article](accessor)
+
+	trashed, err := articles.OnlyDeleted().All()
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(trashed) != 2 {
+		t.Errorf("Expected 2 deleted articles, got %d", len(trashed))
+	}
+
+	article, err := articles.WithDeleted().Get(deleted["Advanced Go"].ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := articles.Restore(article); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if err := articles.HardDelete(article); err != nil {
+		t.Fatalf("HardDelete failed: %v", err)
+	}
+
+	count, err := articles.WithDeleted().Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 articles, got %d", count)
+	}
+}
@@ -14,20 +14,22 @@ func CreateSuperuser(accessor *Accessor, username, email, password string) error
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	// Check if user already exists
-	existingUser := &User{}
-	err := accessor.Get(existingUser, username)
-	if err == nil {
+	// Check if user already exists. Filter and CountFilter work on every
+	// backend, unlike QuerySets.
+	var existingUsers []User
+	if err := accessor.Filter(&existingUsers, map[string]interface{}{"username": username}); err != nil {
+		return fmt.Errorf("failed to check username uniqueness: %w", err)
+	}
+	if len(existingUsers) > 0 {
 		return fmt.Errorf("user with username '%s' already exists", username)
 	}
 
 	// Check if email already exists
-	var users []User
-	err = accessor.Filter(&users, map[string]interface{}{"email": email})
+	emailCount, err := accessor.CountFilter(&User{}, map[string]interface{}{"email": email})
 	if err != nil {
 		return fmt.Errorf("failed to check email uniqueness: %w", err)
 	}
-	if len(users) > 0 {
+	if emailCount > 0 {
 		return fmt.Errorf("user with email '%s' already exists", email)
 	}
 
@@ -46,8 +48,12 @@ func CreateSuperuser(accessor *Accessor, username, email, password string) error
 		return fmt.Errorf("failed to set password: %w", err)
 	}
 
-	// Save to database
+	// Save to database. A concurrent insert can still win the race between
+	// the checks above and this point, which surfaces as a unique violation.
 	if err := accessor.Create(superuser); err != nil {
+		if errors.Is(err, ErrUniqueViolation) {
+			return fmt.Errorf("user with username '%s' or email '%s' already exists: %w", username, email, err)
+		}
 		return fmt.Errorf("failed to create superuser: %w", err)
 	}
 
@@ -1,6 +1,7 @@
 package gobase
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -385,6 +386,44 @@ func TestCreateSuperuser_DuplicateUser(t *testing.T) {
 	}
 }
 
+// TestCreateSuperuser_MemoryBackend tests creating superusers on a backend without QuerySets
+func TestCreateSuperuser_MemoryBackend(t *testing.T) {
+	accessor := NewAccessor(setupMemoryDB(t))
+	if err := accessor.Migrate(&User{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	if err := CreateSuperuser(accessor, "admin", "admin@example.com", "SuperSecret123"); err != nil {
+		t.Fatalf("Failed to create superuser: %v", err)
+	}
+	user := &User{}
+	if err := accessor.Get(user, 1); err != nil {
+		t.Fatalf("Failed to get superuser: %v", err)
+	}
+	if !user.IsSuperuser || !user.CheckPassword("SuperSecret123") {
+		t.Errorf("Expected a superuser with the password, got %+v", user)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		email    string
+		expected string
+	}{
+		{name: "Duplicate username", username: "admin", email: "other@example.com", expected: "username 'admin' already exists"},
+		{name: "Duplicate email", username: "other", email: "admin@example.com", expected: "email 'admin@example.com' already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CreateSuperuser(accessor, tt.username, tt.email, "SuperSecret123")
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
 // TestUserModelValidation tests User model validation during creation
 func TestUserModelValidation(t *testing.T) {
 	connection := setupTestDB(t)